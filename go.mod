@@ -1,3 +1,3 @@
 module github.com/Anthony-Fiddes/gonne
 
-go 1.15
+go 1.18
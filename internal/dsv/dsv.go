@@ -0,0 +1,269 @@
+// Package dsv reads and writes matrices as delimiter-separated values, such
+// as CSV and TSV files, with one row of the matrix per line.
+//
+// Files are read and written a row at a time, so a large file is never held
+// in memory as text.
+package dsv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+// errorString represents an error in reading or writing delimited data
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrInvalidValue specifies that a field is not a number.
+	ErrInvalidValue errorString = "dsv: invalid value"
+	// ErrRowLength specifies that a row has a different number of fields
+	// than the first one, or than the dimensions given with WithDimensions.
+	ErrRowLength errorString = "dsv: rows have different lengths"
+	// ErrRowCount specifies that a file has a different number of rows than
+	// the dimensions given with WithDimensions, or none at all.
+	ErrRowCount errorString = "dsv: unexpected number of rows"
+	// ErrInvalidDimensions specifies that the dimensions given with
+	// WithDimensions aren't positive, or are too large for a matrix.
+	ErrInvalidDimensions errorString = "dsv: invalid dimensions"
+)
+
+// maxPreallocated is the most entries ReadDense makes room for before
+// reading them.
+const maxPreallocated = 1 << 16
+
+const (
+	unexpectedReadErr  = "dsv: unexpected error while reading: %w"
+	unexpectedWriteErr = "dsv: unexpected error while writing: %w"
+)
+
+// Option configures how delimited data is read or written.
+type Option func(*options)
+
+type options struct {
+	delimiter   rune
+	header      bool
+	columnNames []string
+	rows, cols  int
+	dimensions  bool
+}
+
+// WithDelimiter sets the rune that separates the fields of a row. The
+// default is a comma; use '\t' for TSV.
+func WithDelimiter(delimiter rune) Option {
+	return func(o *options) {
+		o.delimiter = delimiter
+	}
+}
+
+// WithHeader makes a Reader treat the first line as a header of column
+// names rather than a row of the matrix. It is ignored when writing.
+func WithHeader() Option {
+	return func(o *options) {
+		o.header = true
+	}
+}
+
+// WithColumnNames makes Write start with a header line of column names,
+// which there must be one of for every col. It is ignored when reading.
+func WithColumnNames(names ...string) Option {
+	return func(o *options) {
+		o.columnNames = names
+	}
+}
+
+// WithDimensions makes a Reader check that the data has exactly the given
+// number of rows and cols, so that a truncated or malformed file is caught.
+// Both must be positive, or Read returns ErrInvalidDimensions. It is ignored
+// when writing.
+func WithDimensions(rows, cols int) Option {
+	return func(o *options) {
+		o.rows, o.cols = rows, cols
+		o.dimensions = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{delimiter: ','}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Reader reads the rows of delimited data one at a time.
+type Reader struct {
+	csv     *csv.Reader
+	options options
+	header  []string
+	row     []float64
+	// rows is the number of rows read so far.
+	rows int
+	cols int
+}
+
+// NewReader returns a reader of delimited data. Fields may be quoted as in
+// CSV files, and blank lines are skipped.
+func NewReader(r io.Reader, opts ...Option) *Reader {
+	o := newOptions(opts)
+	reader := csv.NewReader(r)
+	reader.Comma = o.delimiter
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	reader.TrimLeadingSpace = true
+	return &Reader{csv: reader, options: o, cols: o.cols}
+}
+
+// Header returns the column names of data read WithHeader, once Read has
+// been called.
+func (r *Reader) Header() []string {
+	return r.header
+}
+
+// Read returns the next row. It returns io.EOF once every row has been
+// read. The slice is reused by the next call to Read.
+//
+// The error wraps ErrInvalidValue if a field isn't a number, ErrRowLength
+// if the row doesn't have as many fields as the first one, or ErrRowCount
+// if there are no rows or they don't match the dimensions given with
+// WithDimensions. It wraps ErrInvalidDimensions if those dimensions aren't
+// valid.
+func (r *Reader) Read() ([]float64, error) {
+	if err := r.options.dimensionsErr(); err != nil {
+		return nil, err
+	}
+	if r.options.header && r.header == nil {
+		record, err := r.csv.Read()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: the header is missing", ErrRowCount)
+		}
+		if err != nil {
+			return nil, fmt.Errorf(unexpectedReadErr, err)
+		}
+		r.header = append([]string(nil), record...)
+	}
+
+	record, err := r.csv.Read()
+	if err == io.EOF {
+		switch {
+		case r.rows == 0:
+			return nil, fmt.Errorf("%w: there are no rows", ErrRowCount)
+		case r.options.rows > 0 && r.rows != r.options.rows:
+			return nil, fmt.Errorf("%w: expected %d rows, instead there are %d", ErrRowCount, r.options.rows, r.rows)
+		}
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf(unexpectedReadErr, err)
+	}
+	line, _ := r.csv.FieldPos(0)
+	if r.options.rows > 0 && r.rows == r.options.rows {
+		return nil, fmt.Errorf("%w: line %d: expected %d rows, instead there are more", ErrRowCount, line, r.options.rows)
+	}
+	if r.cols == 0 {
+		r.cols = len(record)
+	}
+	if len(record) != r.cols {
+		return nil, fmt.Errorf("%w: line %d has %d fields, expected %d", ErrRowLength, line, len(record), r.cols)
+	}
+
+	if r.row == nil {
+		r.row = make([]float64, r.cols)
+	}
+	for i, field := range record {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d, field %d: %q is not a number", ErrInvalidValue, line, i+1, field)
+		}
+		r.row[i] = value
+	}
+	r.rows++
+	return r.row, nil
+}
+
+// dimensionsErr returns an error if the dimensions given with WithDimensions
+// aren't positive or their product overflows an int.
+func (o options) dimensionsErr() error {
+	switch {
+	case !o.dimensions:
+		return nil
+	case o.rows <= 0 || o.cols <= 0:
+		return fmt.Errorf("%w: %dx%d", ErrInvalidDimensions, o.rows, o.cols)
+	case o.cols > math.MaxInt/o.rows:
+		return fmt.Errorf("%w: a %dx%d matrix is too large", ErrInvalidDimensions, o.rows, o.cols)
+	}
+	return nil
+}
+
+// ReadDense reads delimited data into a matrix, converting its entries to T.
+// See Reader.Read for the errors it returns.
+func ReadDense[T matrix.Float](r io.Reader, opts ...Option) (*matrix.Dense[T], error) {
+	reader := NewReader(r, opts...)
+	if err := reader.options.dimensionsErr(); err != nil {
+		return nil, err
+	}
+	size := reader.options.rows * reader.options.cols
+	if size > maxPreallocated {
+		size = maxPreallocated
+	}
+	data := make([]T, 0, size)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return matrix.NewFromSlice(data, reader.rows, reader.cols), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range row {
+			data = append(data, T(value))
+		}
+	}
+}
+
+// Read reads delimited data into a float64 matrix. See ReadDense.
+func Read(r io.Reader, opts ...Option) (*matrix.Matrix, error) {
+	return ReadDense[float64](r, opts...)
+}
+
+// Write writes a matrix as delimited data, a row per line. Entries are
+// written with the fewest digits that represent them exactly.
+func Write[T matrix.Float](w io.Writer, m *matrix.Dense[T], opts ...Option) error {
+	o := newOptions(opts)
+	rows, cols := m.Dimensions()
+	if o.columnNames != nil && len(o.columnNames) != cols {
+		return fmt.Errorf("%w: %d column names for %d cols", ErrRowLength, len(o.columnNames), cols)
+	}
+	bitSize := 64
+	if _, ok := any(T(0)).(float32); ok {
+		bitSize = 32
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = o.delimiter
+	if o.columnNames != nil {
+		// Errors are kept by the writer and returned by Error.
+		writer.Write(o.columnNames)
+	}
+	record := make([]string, cols)
+	for r := 0; r < rows; r++ {
+		for c := range record {
+			record[c] = strconv.FormatFloat(float64(m.Get(r, c)), 'g', -1, bitSize)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf(unexpectedWriteErr, err)
+	}
+	return nil
+}
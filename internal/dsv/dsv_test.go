@@ -0,0 +1,136 @@
+package dsv_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/dsv"
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestRead(t *testing.T) {
+	expected := matrix.Parse("1 -2.5 3\n4e-07 5 6")
+	tests := []struct {
+		name    string
+		text    string
+		options []dsv.Option
+	}{
+		{"CSV", "1,-2.5,3\n4e-07,5,6\n", nil},
+		{"Spaces and CRLF", "1, -2.5 ,3\r\n\r\n4e-7, 5, 6", nil},
+		{"Quoted", "\"1\",\"-2.5\",3\n4e-07,5,\"6\"\n", nil},
+		{"TSV", "1\t-2.5\t3\n4e-07\t5\t6\n", []dsv.Option{dsv.WithDelimiter('\t')}},
+		{"Header", "a,b,c\n1,-2.5,3\n4e-07,5,6\n", []dsv.Option{dsv.WithHeader()}},
+		{"Dimensions", "1,-2.5,3\n4e-07,5,6\n", []dsv.Option{dsv.WithDimensions(2, 3)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := dsv.Read(strings.NewReader(test.text), test.options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !matrix.Equal(result, expected) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	reader := dsv.NewReader(strings.NewReader("x;y\n1;2\n3;4\n"), dsv.WithDelimiter(';'), dsv.WithHeader())
+	var rows [][]float64
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rows = append(rows, append([]float64(nil), row...))
+	}
+	if expected := [][]float64{{1, 2}, {3, 4}}; !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected the rows %v, instead got %v", expected, rows)
+	}
+	if header := reader.Header(); !reflect.DeepEqual(header, []string{"x", "y"}) {
+		t.Fatalf("expected the header [x y], instead got %v", header)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		options []dsv.Option
+		want    error
+	}{
+		{"Empty", "", nil, dsv.ErrRowCount},
+		{"Only a header", "a,b\n", []dsv.Option{dsv.WithHeader()}, dsv.ErrRowCount},
+		{"Ragged", "1,2\n3\n", nil, dsv.ErrRowLength},
+		{"Not a number", "1,2\n3,four\n", nil, dsv.ErrInvalidValue},
+		{"Too few rows", "1,2\n", []dsv.Option{dsv.WithDimensions(2, 2)}, dsv.ErrRowCount},
+		{"Too many rows", "1,2\n3,4\n5,6\n", []dsv.Option{dsv.WithDimensions(2, 2)}, dsv.ErrRowCount},
+		{"Too few cols", "1,2\n3,4\n", []dsv.Option{dsv.WithDimensions(2, 3)}, dsv.ErrRowLength},
+		{"Wrong delimiter", "1\t2\n", nil, dsv.ErrInvalidValue},
+		{"Negative rows", "1,2\n", []dsv.Option{dsv.WithDimensions(-1, 2)}, dsv.ErrInvalidDimensions},
+		{"Zero cols", "1,2\n", []dsv.Option{dsv.WithDimensions(1, 0)}, dsv.ErrInvalidDimensions},
+		{"Overflowing dimensions", "1,2\n", []dsv.Option{dsv.WithDimensions(math.MaxInt/2, 3)}, dsv.ErrInvalidDimensions},
+		{"Huge dimensions", "1,2\n", []dsv.Option{dsv.WithDimensions(1<<40, 2)}, dsv.ErrRowCount},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := dsv.Read(strings.NewReader(test.text), test.options...); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	m := matrix.Parse("1 -2.5 3\n1e-300 0.1 6")
+	tests := []struct {
+		name     string
+		options  []dsv.Option
+		expected string
+	}{
+		{"CSV", nil, "1,-2.5,3\n1e-300,0.1,6\n"},
+		{"TSV", []dsv.Option{dsv.WithDelimiter('\t')}, "1\t-2.5\t3\n1e-300\t0.1\t6\n"},
+		{"Column names", []dsv.Option{dsv.WithColumnNames("a", "b,c", "d")}, "a,\"b,c\",d\n1,-2.5,3\n1e-300,0.1,6\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := dsv.Write(&buffer, m, test.options...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buffer.String() != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, buffer.String())
+			}
+		})
+	}
+
+	var buffer bytes.Buffer
+	if err := dsv.Write(&buffer, m.Transpose(), dsv.WithDelimiter('\t')); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result, err := dsv.Read(&buffer, dsv.WithDelimiter('\t')); err != nil || !matrix.Equal(result, m.Transpose()) {
+		t.Fatalf("expected to read back:\n\n%s\n\ninstead got:\n\n%v (%v)", m.Transpose(), result, err)
+	}
+
+	m32 := matrix.Convert[float32](m)
+	buffer.Reset()
+	if err := dsv.Write(&buffer, m32); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result, err := dsv.ReadDense[float32](&buffer); err != nil || !matrix.Equal(result, m32) {
+		t.Fatalf("expected to read back:\n\n%s\n\ninstead got:\n\n%v (%v)", m32, result, err)
+	}
+
+	if err := dsv.Write(&buffer, m, dsv.WithColumnNames("a")); !errors.Is(err, dsv.ErrRowLength) {
+		t.Fatalf("expected an error wrapping %q, instead got %v", dsv.ErrRowLength, err)
+	}
+}
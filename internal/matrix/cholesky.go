@@ -0,0 +1,86 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Cholesky is the Cholesky decomposition of a symmetric positive definite
+// matrix A: A = L * Lᵀ, where L is lower triangular with a positive
+// diagonal. It takes about half the work of an LU decomposition.
+type Cholesky[T Float] struct {
+	l *Dense[T]
+}
+
+// NewCholesky calculates the Cholesky decomposition of a symmetric positive
+// definite matrix. The error wraps ErrNotSquare if the matrix is not square,
+// ErrNotSymmetric if it is not symmetric, or ErrNotPositiveDefinite if it is
+// not positive definite.
+func NewCholesky[T Float](a *Dense[T]) (*Cholesky[T], error) {
+	if err := squareErr(a); err != nil {
+		return nil, err
+	}
+	n := a.rows
+	tolerance := singularTolerance(n, vectorNorm(a, Infinity))
+	if err := symmetricErr(a, tolerance); err != nil {
+		return nil, err
+	}
+
+	l := NewDense[T](n, n)
+	for j := 0; j < n; j++ {
+		d := a.Get(j, j)
+		if j > 0 {
+			d -= Dot(l.Slice(j, j+1, 0, j), l.Slice(j, j+1, 0, j))
+		}
+		// !(d > 0) also catches NaN.
+		if !(d > 0) {
+			return nil, fmt.Errorf(
+				"%w: the leading minor of order %d is not positive",
+				ErrNotPositiveDefinite, j+1,
+			)
+		}
+		diagonal := T(math.Sqrt(float64(d)))
+		l.Set(j, j, diagonal)
+		for i := j + 1; i < n; i++ {
+			v := a.Get(i, j)
+			if j > 0 {
+				v -= Dot(l.Slice(i, i+1, 0, j), l.Slice(j, j+1, 0, j))
+			}
+			l.Set(i, j, v/diagonal)
+		}
+	}
+	return &Cholesky[T]{l: l}, nil
+}
+
+// L returns the lower triangular factor.
+func (d *Cholesky[T]) L() *Dense[T] {
+	return d.l.Clone()
+}
+
+// Determinant returns the determinant of the decomposed matrix.
+func (d *Cholesky[T]) Determinant() T {
+	var result T = 1
+	for i := 0; i < d.l.rows; i++ {
+		result *= d.l.Get(i, i)
+	}
+	return result * result
+}
+
+// Solve returns the matrix x that satisfies A * x = b. The error is a
+// *DimensionError if b doesn't have as many rows as A.
+func (d *Cholesky[T]) Solve(b *Dense[T]) (*Dense[T], error) {
+	if err := rhsErr(d.l, b); err != nil {
+		return nil, err
+	}
+	x := b.Clone()
+	solveLower(d.l, x, false)
+	solveUpper(d.l.Transpose(), x)
+	return x, nil
+}
+
+// Inverse returns the inverse of the decomposed matrix.
+func (d *Cholesky[T]) Inverse() *Dense[T] {
+	// A positive definite matrix is never singular, so Solve can't fail.
+	result, _ := d.Solve(Identity[T](d.l.rows))
+	return result
+}
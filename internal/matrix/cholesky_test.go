@@ -0,0 +1,57 @@
+package matrix_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestCholesky(t *testing.T) {
+	a := matrix.NewFromSlice([]float64{4, 12, -16, 12, 37, -43, -16, -43, 98}, 3, 3)
+	cholesky, err := matrix.NewCholesky(a)
+	if err != nil {
+		t.Fatalf("expected NewCholesky to succeed, instead got %v", err)
+	}
+	expected := "2 0 0\n6 1 0\n-8 5 3"
+	if l := cholesky.L(); l.String() != expected {
+		t.Fatalf("expected L to be:\n\n%s\n\ninstead it was:\n\n%s", expected, l)
+	}
+	if det := cholesky.Determinant(); math.Abs(det-36) > 1e-9 {
+		t.Fatalf("expected a determinant of 36, instead got %f", det)
+	}
+
+	b := matrix.NewFromSlice([]float64{1, 2, 3}, 3, 1)
+	x, err := cholesky.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ax := matrix.Multiply(a, x); !matrix.EqualApprox(ax, b, 1e-9, 1e-9) {
+		t.Fatalf("expected A * x to equal b, instead %v", matrix.Diff(ax, b))
+	}
+	if product := matrix.Multiply(a, cholesky.Inverse()); !matrix.EqualApprox(product, matrix.Identity[float64](3), 1e-9, 0) {
+		t.Fatalf("expected A times its inverse to be the identity, instead %v", matrix.Diff(product, matrix.Identity[float64](3)))
+	}
+}
+
+func TestCholeskyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *matrix.Matrix
+		want error
+	}{
+		{"Not square", matrix.New(2, 3), matrix.ErrNotSquare},
+		{"Not symmetric", matrix.NewFromSlice([]float64{2, 1, 0, 2}, 2, 2), matrix.ErrNotSymmetric},
+		{"Indefinite", matrix.NewFromSlice([]float64{1, 2, 2, 1}, 2, 2), matrix.ErrNotPositiveDefinite},
+		{"Singular", matrix.NewFromSlice([]float64{1, 1, 1, 1}, 2, 2), matrix.ErrNotPositiveDefinite},
+		{"NaN", matrix.NewFromSlice([]float64{math.NaN()}, 1, 1), matrix.ErrNotPositiveDefinite},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := matrix.NewCholesky(test.a); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
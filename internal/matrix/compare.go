@@ -0,0 +1,111 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Equal reports whether two matrices have the same dimensions and exactly
+// the same entries. As with ==, NaN is not equal to anything, including
+// itself.
+func Equal[T Float](first, second *Dense[T]) bool {
+	if first.rows != second.rows || first.cols != second.cols {
+		return false
+	}
+	for r := 0; r < first.rows; r++ {
+		for c := 0; c < first.cols; c++ {
+			if first.Get(r, c) != second.Get(r, c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// EqualApprox reports whether two matrices have the same dimensions and
+// entries that are each within absTol of each other, or within relTol
+// relative to the larger of their magnitudes. Infinite entries must match
+// exactly and NaN entries only match other NaNs, so that results that blew up
+// are never mistaken for ones that didn't.
+func EqualApprox[T Float](first, second *Dense[T], absTol, relTol float64) bool {
+	if first.rows != second.rows || first.cols != second.cols {
+		return false
+	}
+	for r := 0; r < first.rows; r++ {
+		for c := 0; c < first.cols; c++ {
+			x, y := float64(first.Get(r, c)), float64(second.Get(r, c))
+			if !withinTolerance(x, y, absTol, relTol) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func withinTolerance(x, y, absTol, relTol float64) bool {
+	if x == y || math.IsNaN(x) && math.IsNaN(y) {
+		return true
+	}
+	if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return false
+	}
+	d := math.Abs(x - y)
+	return d <= absTol || d <= relTol*math.Max(math.Abs(x), math.Abs(y))
+}
+
+// Difference describes the entry at which two matrices differ the most.
+type Difference struct {
+	// Row and Col are the position of the entry.
+	Row, Col int
+	// First and Second are the entry in each matrix.
+	First, Second float64
+	// Abs is the absolute difference between the entries and Rel is Abs
+	// relative to the larger of their magnitudes. Both are +Inf if only one
+	// of the entries is NaN or infinite.
+	Abs, Rel float64
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf(
+		"the largest difference is at row %d, col %d: %g and %g (absolute %g, relative %g)",
+		d.Row, d.Col, d.First, d.Second, d.Abs, d.Rel,
+	)
+}
+
+// Diff returns the entry at which two matrices differ the most, measured by
+// the absolute difference. If more than one entry differs by the same
+// amount, the first in row-major order is returned. Its String method makes
+// a useful message for a failed test:
+//
+//	if !matrix.EqualApprox(want, got, 1e-9, 1e-9) {
+//		t.Fatal(matrix.Diff(want, got))
+//	}
+//
+// The matrices must have the same dimensions.
+func Diff[T Float](first, second *Dense[T]) Difference {
+	sameDimsCheck(first, second)
+	worst := Difference{Row: -1}
+	for r := 0; r < first.rows; r++ {
+		for c := 0; c < first.cols; c++ {
+			d := difference(float64(first.Get(r, c)), float64(second.Get(r, c)))
+			d.Row, d.Col = r, c
+			if worst.Row < 0 || d.Abs > worst.Abs {
+				worst = d
+			}
+		}
+	}
+	return worst
+}
+
+func difference(x, y float64) Difference {
+	d := Difference{First: x, Second: y}
+	switch {
+	case x == y || math.IsNaN(x) && math.IsNaN(y):
+	case math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0):
+		d.Abs, d.Rel = math.Inf(1), math.Inf(1)
+	default:
+		d.Abs = math.Abs(x - y)
+		d.Rel = d.Abs / math.Max(math.Abs(x), math.Abs(y))
+	}
+	return d
+}
@@ -0,0 +1,86 @@
+package matrix_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestEqual(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	nan := matrix.NewFromSlice([]float64{math.NaN()}, 1, 1)
+	tests := []struct {
+		name          string
+		first, second *matrix.Matrix
+		expected      bool
+	}{
+		{"Same", m, m.Clone(), true},
+		{"View", m.Transpose().Transpose(), m, true},
+		{"Different entry", m, matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6.000001}, 2, 3), false},
+		{"Different dimensions", m, matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 3, 2), false},
+		{"NaN", nan, nan, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := matrix.Equal(test.first, test.second); result != test.expected {
+				t.Fatalf("expected Equal to return %v for:\n%s\nand\n%s\ninstead it returned %v", test.expected, test.first, test.second, result)
+			}
+		})
+	}
+}
+
+func TestEqualApprox(t *testing.T) {
+	tests := []struct {
+		name           string
+		first, second  []float64
+		absTol, relTol float64
+		expected       bool
+	}{
+		{"Exact", []float64{1, 2}, []float64{1, 2}, 0, 0, true},
+		{"Within absolute", []float64{0, 1}, []float64{1e-10, 1}, 1e-9, 0, true},
+		{"Outside absolute", []float64{0, 1}, []float64{1e-8, 1}, 1e-9, 0, false},
+		{"Within relative", []float64{1e6, 1}, []float64{1e6 + 0.5, 1}, 0, 1e-6, true},
+		{"Outside relative", []float64{1e6, 1}, []float64{1e6 + 2, 1}, 0, 1e-6, false},
+		{"Both NaN", []float64{math.NaN(), 1}, []float64{math.NaN(), 1}, 0, 0, true},
+		{"One NaN", []float64{math.NaN(), 1}, []float64{0, 1}, math.Inf(1), 0, false},
+		{"Same infinity", []float64{math.Inf(1), 1}, []float64{math.Inf(1), 1}, 0, 0, true},
+		{"Infinity", []float64{math.Inf(1), 1}, []float64{1e300, 1}, 0, 1, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first := matrix.NewFromSlice(test.first, 1, 2)
+			second := matrix.NewFromSlice(test.second, 1, 2)
+			if result := matrix.EqualApprox(first, second, test.absTol, test.relTol); result != test.expected {
+				t.Fatalf("expected EqualApprox to return %v for %v and %v, instead it returned %v", test.expected, test.first, test.second, result)
+			}
+		})
+	}
+
+	if matrix.EqualApprox(matrix.New(2, 3), matrix.New(3, 2), 1, 1) {
+		t.Fatalf("expected EqualApprox to return false for matrices with different dimensions")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	first := matrix.NewFromSlice([]float32{1, 2, 3, 4}, 2, 2)
+	second := matrix.NewFromSlice([]float32{1, 2.5, 3, 3}, 2, 2)
+	d := matrix.Diff(first, second)
+	expected := matrix.Difference{Row: 1, Col: 1, First: 4, Second: 3, Abs: 1, Rel: 0.25}
+	if d != expected {
+		t.Fatalf("expected Diff to return %+v, instead it returned %+v", expected, d)
+	}
+	if !strings.Contains(d.String(), "row 1, col 1") {
+		t.Fatalf("expected the message to contain the position of the difference, instead it was %q", d)
+	}
+
+	if d := matrix.Diff(first, first); d.Row != 0 || d.Col != 0 || d.Abs != 0 {
+		t.Fatalf("expected identical matrices to differ by 0 at the first entry, instead got %+v", d)
+	}
+
+	nan := matrix.NewFromSlice([]float64{1, math.NaN()}, 1, 2)
+	if d := matrix.Diff(nan, matrix.NewFromSlice([]float64{2, 0}, 1, 2)); d.Col != 1 || !math.IsInf(d.Abs, 1) {
+		t.Fatalf("expected a NaN to be the largest difference, instead got %+v", d)
+	}
+}
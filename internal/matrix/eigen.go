@@ -0,0 +1,147 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// jacobiSweeps is the most sweeps over every pair of rows or columns that
+// the Jacobi algorithms make. They converge quadratically, so they normally
+// need fewer than 15.
+const jacobiSweeps = 60
+
+// EigenSym is the eigendecomposition of a symmetric matrix A:
+// A = V * diag(values) * Vᵀ, where the columns of V are orthonormal
+// eigenvectors. The eigenvalues are in descending order, and column i of V
+// belongs to eigenvalue i.
+type EigenSym[T Float] struct {
+	values  []T
+	vectors *Dense[T]
+}
+
+// NewEigenSym calculates the eigendecomposition of a symmetric matrix with
+// the cyclic Jacobi algorithm, which is slower than the alternatives for
+// large matrices but very accurate. The error wraps ErrNotSquare or
+// ErrNotSymmetric if the matrix is not square or not symmetric, or
+// ErrNoConvergence in the unlikely case that it fails to converge.
+func NewEigenSym[T Float](a *Dense[T]) (*EigenSym[T], error) {
+	if err := squareErr(a); err != nil {
+		return nil, err
+	}
+	n := a.rows
+	scale := vectorNorm(a, Frobenius)
+	tolerance := singularTolerance(n, vectorNorm(a, Infinity))
+	if err := symmetricErr(a, tolerance); err != nil {
+		return nil, err
+	}
+
+	work := a.Clone()
+	// The eigenvectors are accumulated as the rows of their transpose, so
+	// that every rotation is of contiguous rows.
+	vt := Identity[T](n)
+	// Entries below this are negligible next to the matrix as a whole.
+	negligible := T(epsilon[T]()) * scale / T(n)
+	converged := false
+	for sweep := 0; sweep < jacobiSweeps && !converged; sweep++ {
+		converged = true
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := work.Get(p, q)
+				app, aqq := work.Get(p, p), work.Get(q, q)
+				if abs(apq) <= negligible ||
+					abs(apq) <= T(epsilon[T]())*T(math.Sqrt(float64(abs(app*aqq)))) {
+					continue
+				}
+				converged = false
+
+				// Choose the rotation that zeroes work[p][q], taking the
+				// smaller of the two possible angles for stability.
+				theta := (aqq - app) / (2 * apq)
+				t := 1 / (abs(theta) + T(math.Sqrt(float64(theta*theta+1))))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / T(math.Sqrt(float64(t*t+1)))
+				s := t * c
+
+				// Rotating both the rows and the columns of work keeps it
+				// symmetric, so the columns can be copied from the rotated
+				// rows. The 2x2 block where they cross is known exactly.
+				rotateRows(work, p, q, c, s)
+				for i := 0; i < n; i++ {
+					work.data[i*n+p] = work.data[p*n+i]
+					work.data[i*n+q] = work.data[q*n+i]
+				}
+				work.Set(p, p, app-t*apq)
+				work.Set(q, q, aqq+t*apq)
+				work.Set(p, q, 0)
+				work.Set(q, p, 0)
+				rotateRows(vt, p, q, c, s)
+			}
+		}
+	}
+	if !converged {
+		return nil, fmt.Errorf("%w: no eigendecomposition after %d sweeps", ErrNoConvergence, jacobiSweeps)
+	}
+
+	values := make([]T, n)
+	for i := range values {
+		values[i] = work.Get(i, i)
+	}
+	order := descending(values)
+	return &EigenSym[T]{values: permute(values, order), vectors: permuteCols(vt.Transpose(), order)}, nil
+}
+
+// Values returns the eigenvalues in descending order.
+func (e *EigenSym[T]) Values() []T {
+	result := make([]T, len(e.values))
+	copy(result, e.values)
+	return result
+}
+
+// Vectors returns a matrix whose columns are the eigenvectors, in the same
+// order as the eigenvalues.
+func (e *EigenSym[T]) Vectors() *Dense[T] {
+	return e.vectors.Clone()
+}
+
+// rotateRows applies a plane rotation to rows p and q of a matrix with
+// contiguous rows: p = c*p - s*q and q = s*p + c*q.
+func rotateRows[T Float](m *Dense[T], p, q int, c, s T) {
+	x, y := m.rawRow(p), m.rawRow(q)
+	for i := range x {
+		xi, yi := x[i], y[i]
+		x[i] = c*xi - s*yi
+		y[i] = s*xi + c*yi
+	}
+}
+
+// descending returns the indices of values ordered from the largest value
+// to the smallest.
+func descending[T Float](values []T) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] > values[order[j]]
+	})
+	return order
+}
+
+func permute[T Float](values []T, order []int) []T {
+	result := make([]T, len(order))
+	for i, o := range order {
+		result[i] = values[o]
+	}
+	return result
+}
+
+func permuteCols[T Float](m *Dense[T], order []int) *Dense[T] {
+	result := NewDense[T](m.rows, len(order))
+	for i, o := range order {
+		Copy(result.ColView(i), m.ColView(o))
+	}
+	return result
+}
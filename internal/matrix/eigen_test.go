@@ -0,0 +1,81 @@
+package matrix_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+// randomSymmetric returns a random n x n symmetric matrix.
+func randomSymmetric(rng *rand.Rand, n int) *matrix.Matrix {
+	m := matrix.NewRandomNormalFrom(rng, n, n)
+	return matrix.Add(m, m.Transpose())
+}
+
+func TestEigenSym(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name     string
+		a        *matrix.Matrix
+		expected []float64 // nil to only check the decomposition
+	}{
+		{"Diagonal", matrix.NewFromSlice([]float64{1, 0, 0, 0, 3, 0, 0, 0, 2}, 3, 3), []float64{3, 2, 1}},
+		{"2x2", matrix.NewFromSlice([]float64{2, 1, 1, 2}, 2, 2), []float64{3, 1}},
+		{"Zero diagonal", matrix.NewFromSlice([]float64{0, 1, 1, 0}, 2, 2), []float64{1, -1}},
+		{"Singular", matrix.NewFromSlice([]float64{1, 1, 1, 1}, 2, 2), []float64{2, 0}},
+		{"1x1", matrix.NewFromSlice([]float64{-4}, 1, 1), []float64{-4}},
+		{"Random", randomSymmetric(rng, 12), nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eigen, err := matrix.NewEigenSym(test.a)
+			if err != nil {
+				t.Fatalf("expected NewEigenSym to succeed, instead got %v", err)
+			}
+			values, vectors := eigen.Values(), eigen.Vectors()
+			for i, expected := range test.expected {
+				if math.Abs(values[i]-expected) > 1e-12 {
+					t.Fatalf("expected the eigenvalues %v, instead got %v", test.expected, values)
+				}
+			}
+			for i := 1; i < len(values); i++ {
+				if values[i] > values[i-1] {
+					t.Fatalf("expected the eigenvalues in descending order, instead got %v", values)
+				}
+			}
+
+			n := len(values)
+			if vtv := matrix.Multiply(vectors.Transpose(), vectors); !matrix.EqualApprox(vtv, matrix.Identity[float64](n), 1e-12, 0) {
+				t.Fatalf("expected orthonormal eigenvectors, instead %v", matrix.Diff(vtv, matrix.Identity[float64](n)))
+			}
+			for i, value := range values {
+				v := vectors.ColView(i)
+				av, scaled := matrix.Multiply(test.a, v), matrix.Scale(v, value)
+				if !matrix.EqualApprox(av, scaled, 1e-11, 1e-11) {
+					t.Fatalf("expected A * v = %f * v for eigenvector %d, instead %v", value, i, matrix.Diff(av, scaled))
+				}
+			}
+		})
+	}
+}
+
+func TestEigenSymErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *matrix.Matrix
+		want error
+	}{
+		{"Not square", matrix.New(2, 3), matrix.ErrNotSquare},
+		{"Not symmetric", matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2), matrix.ErrNotSymmetric},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := matrix.NewEigenSym(test.a); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package matrix
+
+import "sync/atomic"
+
+// Engine performs the arithmetic behind the matrix operations, so that a
+// simple reference implementation can be swapped for an optimized one.
+//
+// The functions in this package validate their arguments before handing them
+// to the Engine, so an Engine can assume that every matrix it is given has
+// the dimensions the operation requires. Operands that were broadcast are
+// passed as views with a stride of 0 and must never be written to, nor kept
+// after the operation returns, since they are reused. Unless noted
+// otherwise, dst may be exactly one of the operands, but never shares data
+// with them in any other way.
+type Engine[T Float] interface {
+	// Name describes the Engine.
+	Name() string
+
+	// Gemm computes dst = alpha*a*b + beta*dst. dst never shares data with a
+	// or b. When beta is 0 the previous contents of dst are ignored.
+	Gemm(alpha T, a, b *Dense[T], beta T, dst *Dense[T])
+	// Axpy computes y = alpha*x + y.
+	Axpy(alpha T, x, y *Dense[T])
+	// Scale computes dst = alpha*a.
+	Scale(dst, a *Dense[T], alpha T)
+	// Copy copies the entries of src into dst.
+	Copy(dst, src *Dense[T])
+
+	// Add computes dst = a + b element-wise.
+	Add(dst, a, b *Dense[T])
+	// Sub computes dst = a - b element-wise.
+	Sub(dst, a, b *Dense[T])
+	// Mul computes dst = a * b element-wise.
+	Mul(dst, a, b *Dense[T])
+	// Div computes dst = a / b element-wise.
+	Div(dst, a, b *Dense[T])
+	// Map sets every entry of dst to function applied to the entry of a.
+	Map(dst, a *Dense[T], function func(T) T)
+	// Zip sets every entry of dst to function applied to the entries of a
+	// and b.
+	Zip(dst, a, b *Dense[T], function func(x, y T) T)
+
+	// Sum returns the sum of the entries of a.
+	Sum(a *Dense[T]) T
+	// Dot returns the sum of the products of the entries of a and b.
+	Dot(a, b *Dense[T]) T
+	// ArgMax returns the position of the first largest entry of a in
+	// row-major order.
+	ArgMax(a *Dense[T]) (row, col int)
+	// ArgMin returns the position of the first smallest entry of a in
+	// row-major order.
+	ArgMin(a *Dense[T]) (row, col int)
+}
+
+// engineBox lets Engines of different types be stored in an atomic.Value.
+type engineBox[T Float] struct {
+	Engine[T]
+}
+
+// Each type of matrix has its own current Engine.
+var float32Engine, float64Engine atomic.Value
+
+func init() {
+	float32Engine.Store(engineBox[float32]{OptimizedEngine[float32]{}})
+	float64Engine.Store(engineBox[float64]{OptimizedEngine[float64]{}})
+}
+
+func engineValue[T Float]() *atomic.Value {
+	if isFloat32[T]() {
+		return &float32Engine
+	}
+	return &float64Engine
+}
+
+func engine[T Float]() Engine[T] {
+	return engineValue[T]().Load().(engineBox[T]).Engine
+}
+
+// SetEngine sets the Engine used by every operation on matrices of type T.
+// The default is an OptimizedEngine. It is safe to call SetEngine
+// concurrently with other operations, although each operation may use
+// either Engine.
+func SetEngine[T Float](e Engine[T]) {
+	if e == nil {
+		panic("matrix: the engine cannot be nil")
+	}
+	engineValue[T]().Store(engineBox[T]{e})
+}
+
+// CurrentEngine returns the Engine used by every operation on matrices of
+// type T.
+func CurrentEngine[T Float]() Engine[T] {
+	return engine[T]()
+}
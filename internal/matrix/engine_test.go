@@ -0,0 +1,55 @@
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/matrix/enginetest"
+)
+
+func TestEngines(t *testing.T) {
+	t.Run("float64", func(t *testing.T) {
+		engines := []matrix.Engine[float64]{
+			matrix.ReferenceEngine[float64]{},
+			matrix.OptimizedEngine[float64]{},
+		}
+		for _, e := range engines {
+			t.Run(e.Name(), func(t *testing.T) {
+				enginetest.Run(t, e)
+			})
+		}
+	})
+
+	t.Run("float32", func(t *testing.T) {
+		engines := []matrix.Engine[float32]{
+			matrix.ReferenceEngine[float32]{},
+			matrix.OptimizedEngine[float32]{},
+		}
+		for _, e := range engines {
+			t.Run(e.Name(), func(t *testing.T) {
+				enginetest.Run(t, e)
+			})
+		}
+	})
+}
+
+func TestSetEngine(t *testing.T) {
+	previous := matrix.CurrentEngine[float64]()
+	defer matrix.SetEngine(previous)
+
+	matrix.SetEngine[float64](matrix.ReferenceEngine[float64]{})
+	if name := matrix.CurrentEngine[float64]().Name(); name != "reference" {
+		t.Fatalf("expected the current engine to be the reference engine, instead it was %q", name)
+	}
+	if name := matrix.CurrentEngine[float32]().Name(); name != "optimized" {
+		t.Fatalf("expected the float32 engine to be unchanged, instead it was %q", name)
+	}
+	result := matrix.Multiply(
+		matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2),
+		matrix.NewFromSlice([]float64{5, 6, 7, 8}, 2, 2),
+	)
+	expected := "19 22\n43 50"
+	if result.String() != expected {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+	}
+}
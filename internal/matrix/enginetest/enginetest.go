@@ -0,0 +1,344 @@
+// Package enginetest supplies a conformance test suite for implementations
+// of matrix.Engine
+package enginetest
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+// Run runs every conformance test against the given Engine. Every Engine
+// must pass it.
+func Run[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	t.Run("Gemm", func(t *testing.T) { testGemm(t, e) })
+	t.Run("Axpy", func(t *testing.T) { testAxpy(t, e) })
+	t.Run("Scale", func(t *testing.T) { testScale(t, e) })
+	t.Run("Copy", func(t *testing.T) { testCopy(t, e) })
+	t.Run("ElementWise", func(t *testing.T) { testElementWise(t, e) })
+	t.Run("Reductions", func(t *testing.T) { testReductions(t, e) })
+	t.Run("ArgMaxArgMin", func(t *testing.T) { testArgMaxArgMin(t, e) })
+	t.Run("Broadcasting", func(t *testing.T) { testBroadcasting(t, e) })
+}
+
+var sizes = []struct {
+	rows, cols int
+}{
+	{1, 1},
+	{3, 5},
+	{17, 33},
+}
+
+type operand[T matrix.Float] struct {
+	layout string
+	m      *matrix.Dense[T]
+}
+
+// get returns an entry of m as a float64, so that expected results are
+// calculated as precisely as possible.
+func get[T matrix.Float](m *matrix.Dense[T], row, col int) float64 {
+	return float64(m.Get(row, col))
+}
+
+func randomNormal[T matrix.Float](rows, cols int) *matrix.Dense[T] {
+	return matrix.Convert[T](matrix.NewRandomNormal(rows, cols))
+}
+
+// operands returns random matrices with the given dimensions in each of the
+// layouts an Engine may be given.
+func operands[T matrix.Float](rows, cols int) []operand[T] {
+	return []operand[T]{
+		{"contiguous", randomNormal[T](rows, cols)},
+		{"transposed", randomNormal[T](cols, rows).Transpose()},
+		{"slice", randomNormal[T](rows+2, cols+3).Slice(1, rows+1, 2, cols+2)},
+	}
+}
+
+// destinations returns matrices filled with NaN in each of the layouts an
+// Engine may be given, so that entries that are never written stand out.
+func destinations[T matrix.Float](rows, cols int) []operand[T] {
+	result := operands[T](rows, cols)
+	for _, dst := range result {
+		fill(dst.m, T(math.NaN()))
+	}
+	return result
+}
+
+func fill[T matrix.Float](m *matrix.Dense[T], value T) {
+	rows, cols := m.Dimensions()
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			m.Set(r, c, value)
+		}
+	}
+}
+
+// tolerance returns the relative error allowed in a result of type T.
+func tolerance[T matrix.Float]() float64 {
+	var zero T
+	if _, ok := any(zero).(float32); ok {
+		return 1e-4
+	}
+	return 1e-9
+}
+
+func approxEqual[T matrix.Float](expected float64, result T) bool {
+	got := float64(result)
+	if math.IsNaN(expected) || math.IsInf(expected, 0) {
+		return math.IsNaN(got) == math.IsNaN(expected) &&
+			math.IsInf(got, 1) == math.IsInf(expected, 1) &&
+			math.IsInf(got, -1) == math.IsInf(expected, -1)
+	}
+	return math.Abs(expected-got) <= tolerance[T]()*math.Max(1, math.Abs(expected))
+}
+
+// check compares every entry of result with the value returned by expected.
+func check[T matrix.Float](t *testing.T, result *matrix.Dense[T], expected func(r, c int) float64) {
+	t.Helper()
+	rows, cols := result.Dimensions()
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if want, got := expected(r, c), result.Get(r, c); !approxEqual(want, got) {
+				t.Fatalf(
+					"At row %d, col %d the result was expected to be %f, instead it was %f",
+					r, c, want, got,
+				)
+			}
+		}
+	}
+}
+
+func testGemm[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	const depth = 7
+	for _, size := range sizes {
+		for _, a := range operands[T](size.rows, depth) {
+			for _, b := range operands[T](depth, size.cols) {
+				for _, dst := range destinations[T](size.rows, size.cols) {
+					name := fmt.Sprintf(
+						"%dx%d %s x %s into %s",
+						size.rows, size.cols, a.layout, b.layout, dst.layout,
+					)
+					t.Run(name, func(t *testing.T) {
+						product := func(r, c int) float64 {
+							var sum float64
+							for i := 0; i < depth; i++ {
+								sum += get(a.m, r, i) * get(b.m, i, c)
+							}
+							return sum
+						}
+
+						e.Gemm(2, a.m, b.m, 0, dst.m)
+						check(t, dst.m, func(r, c int) float64 {
+							return 2 * product(r, c)
+						})
+
+						previous := dst.m.Clone()
+						e.Gemm(-1, a.m, b.m, 0.5, dst.m)
+						check(t, dst.m, func(r, c int) float64 {
+							return 0.5*get(previous, r, c) - product(r, c)
+						})
+					})
+				}
+			}
+		}
+	}
+}
+
+func testAxpy[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	for _, size := range sizes {
+		for _, x := range operands[T](size.rows, size.cols) {
+			for _, y := range operands[T](size.rows, size.cols) {
+				name := fmt.Sprintf("%dx%d %s to %s", size.rows, size.cols, x.layout, y.layout)
+				t.Run(name, func(t *testing.T) {
+					previous := y.m.Clone()
+					e.Axpy(-1.5, x.m, y.m)
+					check(t, y.m, func(r, c int) float64 {
+						return -1.5*get(x.m, r, c) + get(previous, r, c)
+					})
+				})
+			}
+		}
+	}
+}
+
+func testScale[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	for _, size := range sizes {
+		for _, a := range operands[T](size.rows, size.cols) {
+			for _, dst := range destinations[T](size.rows, size.cols) {
+				name := fmt.Sprintf("%dx%d %s into %s", size.rows, size.cols, a.layout, dst.layout)
+				t.Run(name, func(t *testing.T) {
+					e.Scale(dst.m, a.m, 3)
+					check(t, dst.m, func(r, c int) float64 { return 3 * get(a.m, r, c) })
+				})
+			}
+			t.Run(fmt.Sprintf("%dx%d %s in place", size.rows, size.cols, a.layout), func(t *testing.T) {
+				previous := a.m.Clone()
+				e.Scale(a.m, a.m, 3)
+				check(t, a.m, func(r, c int) float64 { return 3 * get(previous, r, c) })
+			})
+		}
+	}
+}
+
+func testCopy[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	for _, size := range sizes {
+		for _, src := range operands[T](size.rows, size.cols) {
+			for _, dst := range destinations[T](size.rows, size.cols) {
+				name := fmt.Sprintf("%dx%d %s into %s", size.rows, size.cols, src.layout, dst.layout)
+				t.Run(name, func(t *testing.T) {
+					e.Copy(dst.m, src.m)
+					check(t, dst.m, func(r, c int) float64 { return get(src.m, r, c) })
+				})
+			}
+		}
+	}
+}
+
+func testElementWise[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	square := func(x T) T { return x * x }
+	hypot := func(x, y T) T { return T(math.Hypot(float64(x), float64(y))) }
+	operations := []struct {
+		name     string
+		apply    func(dst, a, b *matrix.Dense[T])
+		function func(x, y float64) float64
+	}{
+		{"Add", e.Add, func(x, y float64) float64 { return x + y }},
+		{"Sub", e.Sub, func(x, y float64) float64 { return x - y }},
+		{"Mul", e.Mul, func(x, y float64) float64 { return x * y }},
+		{"Div", e.Div, func(x, y float64) float64 { return x / y }},
+		{
+			"Map",
+			func(dst, a, b *matrix.Dense[T]) { e.Map(dst, a, square) },
+			func(x, y float64) float64 { return x * x },
+		},
+		{
+			"Zip",
+			func(dst, a, b *matrix.Dense[T]) { e.Zip(dst, a, b, hypot) },
+			math.Hypot,
+		},
+	}
+	for _, op := range operations {
+		for _, size := range sizes {
+			for _, a := range operands[T](size.rows, size.cols) {
+				for _, b := range operands[T](size.rows, size.cols) {
+					for _, dst := range destinations[T](size.rows, size.cols) {
+						name := fmt.Sprintf(
+							"%s %dx%d %s and %s into %s",
+							op.name, size.rows, size.cols, a.layout, b.layout, dst.layout,
+						)
+						t.Run(name, func(t *testing.T) {
+							op.apply(dst.m, a.m, b.m)
+							check(t, dst.m, func(r, c int) float64 {
+								return op.function(get(a.m, r, c), get(b.m, r, c))
+							})
+						})
+					}
+
+					name := fmt.Sprintf(
+						"%s %dx%d %s and %s in place",
+						op.name, size.rows, size.cols, a.layout, b.layout,
+					)
+					t.Run(name, func(t *testing.T) {
+						dst := a.m.Clone()
+						previous := a.m.Clone()
+						op.apply(dst, dst, b.m)
+						check(t, dst, func(r, c int) float64 {
+							return op.function(get(previous, r, c), get(b.m, r, c))
+						})
+					})
+				}
+			}
+		}
+	}
+}
+
+func testReductions[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	for _, size := range sizes {
+		for _, a := range operands[T](size.rows, size.cols) {
+			for _, b := range operands[T](size.rows, size.cols) {
+				name := fmt.Sprintf("%dx%d %s and %s", size.rows, size.cols, a.layout, b.layout)
+				t.Run(name, func(t *testing.T) {
+					var sum, dot, magnitude float64
+					for r := 0; r < size.rows; r++ {
+						for c := 0; c < size.cols; c++ {
+							sum += get(a.m, r, c)
+							dot += get(a.m, r, c) * get(b.m, r, c)
+							magnitude += math.Abs(get(a.m, r, c) * get(b.m, r, c))
+						}
+					}
+					// Cancellation makes the error relative to the size of the
+					// entries rather than the size of the result.
+					if result := e.Sum(a.m); !approxEqual(sum, result) {
+						t.Fatalf("expected Sum to return %f, instead it returned %f", sum, result)
+					}
+					if result := e.Dot(a.m, b.m); math.Abs(dot-float64(result)) > tolerance[T]()*math.Max(1, magnitude) {
+						t.Fatalf("expected Dot to return %f, instead it returned %f", dot, result)
+					}
+				})
+			}
+		}
+	}
+}
+
+func testArgMaxArgMin[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	data := []T{
+		3, 1, 9, 9,
+		-4, 9, -4, 0,
+		2, -4, 5, 1,
+	}
+	layouts := []operand[T]{
+		{"contiguous", matrix.NewFromSlice(data, 3, 4)},
+		{"slice", matrix.NewFromSlice(append([]T{0, 0, 0, 0}, data...), 4, 4).Slice(1, 4, 0, 4)},
+	}
+	transposed := matrix.NewDense[T](4, 3)
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 4; c++ {
+			transposed.Set(c, r, data[r*4+c])
+		}
+	}
+	layouts = append(layouts, operand[T]{"transposed", transposed.Transpose()})
+
+	for _, a := range layouts {
+		t.Run(a.layout, func(t *testing.T) {
+			if row, col := e.ArgMax(a.m); row != 0 || col != 2 {
+				t.Fatalf("expected ArgMax to return (0, 2), instead it returned (%d, %d)", row, col)
+			}
+			if row, col := e.ArgMin(a.m); row != 1 || col != 0 {
+				t.Fatalf("expected ArgMin to return (1, 0), instead it returned (%d, %d)", row, col)
+			}
+		})
+	}
+}
+
+// testBroadcasting makes the Engine the package's current Engine so that it
+// is handed the zero-stride views that broadcasting produces.
+func testBroadcasting[T matrix.Float](t *testing.T, e matrix.Engine[T]) {
+	previous := matrix.CurrentEngine[T]()
+	matrix.SetEngine(e)
+	defer matrix.SetEngine(previous)
+
+	for _, size := range sizes {
+		for _, a := range operands[T](size.rows, size.cols) {
+			row := randomNormal[T](1, size.cols)
+			col := randomNormal[T](size.rows, 1)
+			scalar := matrix.NewFromSlice([]T{2}, 1, 1)
+			name := fmt.Sprintf("%dx%d %s", size.rows, size.cols, a.layout)
+			t.Run(name, func(t *testing.T) {
+				check(t, matrix.Add(a.m, row), func(r, c int) float64 {
+					return get(a.m, r, c) + get(row, 0, c)
+				})
+				check(t, matrix.Sub(col, a.m), func(r, c int) float64 {
+					return get(col, r, 0) - get(a.m, r, c)
+				})
+				check(t, matrix.Hadamard(a.m, scalar), func(r, c int) float64 {
+					return get(a.m, r, c) * 2
+				})
+				check(t, matrix.Div(row, col), func(r, c int) float64 {
+					return get(row, 0, c) / get(col, r, 0)
+				})
+			})
+		}
+	}
+}
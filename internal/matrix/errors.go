@@ -0,0 +1,107 @@
+package matrix
+
+import "fmt"
+
+// errorString represents an error in the use of a matrix
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrDimensionMismatch specifies that the dimensions of two matrices
+	// are incompatible with an operation. Errors that wrap it are usually
+	// a *DimensionError, which carries both dimensions.
+	ErrDimensionMismatch errorString = "matrix: dimension mismatch"
+	// ErrInvalidDimensions specifies that a matrix was requested with rows
+	// or cols less than or equal to 0.
+	ErrInvalidDimensions errorString = "matrix: rows and cols cannot be less than or equal to 0"
+	// ErrOutOfRange specifies that a row, column or entry is outside of the
+	// matrix.
+	ErrOutOfRange errorString = "matrix: index out of range"
+	// ErrInvalidLength specifies that a slice does not have the number of
+	// entries needed for a matrix.
+	ErrInvalidLength errorString = "matrix: invalid slice length"
+	// ErrNotSquare specifies that an operation that needs a square matrix
+	// was given a matrix with a different number of rows and cols.
+	ErrNotSquare errorString = "matrix: the matrix must be square"
+	// ErrSingular specifies that a matrix is singular, or too close to
+	// singular for a reliable result, so it has no inverse.
+	ErrSingular errorString = "matrix: the matrix is singular"
+	// ErrNotPositiveDefinite specifies that a symmetric matrix is not
+	// positive definite, as a Cholesky decomposition requires. An asymmetric
+	// matrix gives ErrNotSymmetric instead.
+	ErrNotPositiveDefinite errorString = "matrix: the matrix is not symmetric positive definite"
+	// ErrNotSymmetric specifies that an operation that needs a symmetric
+	// matrix was given one that isn't.
+	ErrNotSymmetric errorString = "matrix: the matrix is not symmetric"
+	// ErrNoConvergence specifies that an iterative algorithm did not reach
+	// an accurate result within its iteration limit.
+	ErrNoConvergence errorString = "matrix: the algorithm did not converge"
+	// ErrInvalidShape specifies that a tensor was requested with no axes or
+	// with an axis whose length is less than or equal to 0, or that a
+	// reshape would change the number of entries.
+	ErrInvalidShape errorString = "matrix: invalid tensor shape"
+	// ErrInvalidAxes specifies that the axes given to an operation are not
+	// valid for the tensor, for example because a permutation repeats one.
+	ErrInvalidAxes errorString = "matrix: invalid axes"
+	// ErrSyntax specifies that text being parsed is not a valid matrix.
+	ErrSyntax errorString = "matrix: invalid syntax"
+)
+
+// DimensionError reports the dimensions of two matrices that are
+// incompatible with an operation. It wraps ErrDimensionMismatch, so it can
+// be detected with errors.Is, and errors.As retrieves the dimensions.
+type DimensionError struct {
+	// First and Second are the dimensions (rows, cols) of the two matrices.
+	First, Second [2]int
+	// Reason explains what the operation requires of the dimensions.
+	Reason string
+}
+
+func newDimensionError[T Float](first, second *Dense[T], reason string) *DimensionError {
+	return &DimensionError{
+		First:  [2]int{first.rows, first.cols},
+		Second: [2]int{second.rows, second.cols},
+		Reason: reason,
+	}
+}
+
+func (e *DimensionError) Error() string {
+	return fmt.Sprintf(
+		"matrix: %s (%dx%d and %dx%d)",
+		e.Reason, e.First[0], e.First[1], e.Second[0], e.Second[1],
+	)
+}
+
+// Unwrap returns ErrDimensionMismatch.
+func (e *DimensionError) Unwrap() error {
+	return ErrDimensionMismatch
+}
+
+// ShapeError reports the shapes of two tensors that are incompatible with an
+// operation. Like DimensionError it wraps ErrDimensionMismatch.
+type ShapeError struct {
+	// First and Second are the shapes of the two tensors.
+	First, Second []int
+	// Reason explains what the operation requires of the shapes.
+	Reason string
+}
+
+func newShapeError(first, second []int, reason string) *ShapeError {
+	return &ShapeError{
+		First:  append([]int(nil), first...),
+		Second: append([]int(nil), second...),
+		Reason: reason,
+	}
+}
+
+func (e *ShapeError) Error() string {
+	return fmt.Sprintf("matrix: %s (%s and %s)", e.Reason, formatShape(e.First), formatShape(e.Second))
+}
+
+// Unwrap returns ErrDimensionMismatch.
+func (e *ShapeError) Unwrap() error {
+	return ErrDimensionMismatch
+}
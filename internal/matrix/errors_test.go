@@ -0,0 +1,80 @@
+package matrix_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestTryErrors(t *testing.T) {
+	a := matrix.New(2, 3)
+	b := matrix.New(3, 2)
+	tests := []struct {
+		name string
+		try  func() error
+		want error
+	}{
+		{"TryNew", func() error { _, err := matrix.TryNew(0, 2); return err }, matrix.ErrInvalidDimensions},
+		{"TryNewDense", func() error { _, err := matrix.TryNewDense[float32](2, -1); return err }, matrix.ErrInvalidDimensions},
+		{"TryNewFromSlice", func() error { _, err := matrix.TryNewFromSlice([]float64{1, 2, 3}, 2, 2); return err }, matrix.ErrInvalidLength},
+		{"TryGet", func() error { _, err := a.TryGet(2, 0); return err }, matrix.ErrOutOfRange},
+		{"TrySet", func() error { return a.TrySet(0, -1, 1) }, matrix.ErrOutOfRange},
+		{"TryAdd", func() error { _, err := matrix.TryAdd(a, b); return err }, matrix.ErrDimensionMismatch},
+		{"TrySub", func() error { _, err := matrix.TrySub(a, b); return err }, matrix.ErrDimensionMismatch},
+		{"TryHadamard", func() error { _, err := matrix.TryHadamard(a, b); return err }, matrix.ErrDimensionMismatch},
+		{"TryDiv", func() error { _, err := matrix.TryDiv(a, b); return err }, matrix.ErrDimensionMismatch},
+		{"TryMultiply", func() error { _, err := matrix.TryMultiply(a, a); return err }, matrix.ErrDimensionMismatch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.try(); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestTrySuccess(t *testing.T) {
+	a := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	if err := a.TrySet(1, 2, 7); err != nil {
+		t.Fatalf("expected TrySet to succeed, instead got %v", err)
+	}
+	if v, err := a.TryGet(1, 2); err != nil || v != 7 {
+		t.Fatalf("expected TryGet to return 7, instead got %v, %v", v, err)
+	}
+	sum, err := matrix.TryAdd(a, matrix.NewFromSlice([]float64{1, 1, 1}, 1, 3))
+	if err != nil {
+		t.Fatalf("expected TryAdd to broadcast, instead got %v", err)
+	}
+	if expected := "2 3 4\n5 6 8"; sum.String() != expected {
+		t.Fatalf("expected TryAdd to return:\n%s\ninstead got:\n%s", expected, sum)
+	}
+	product, err := matrix.TryMultiply(a, a.Transpose())
+	if err != nil {
+		t.Fatalf("expected TryMultiply to succeed, instead got %v", err)
+	}
+	if rows, cols := product.Dimensions(); rows != 2 || cols != 2 {
+		t.Fatalf("expected a 2x2 product, instead got %dx%d", rows, cols)
+	}
+}
+
+func TestDimensionError(t *testing.T) {
+	_, err := matrix.TryMultiply(matrix.New(2, 3), matrix.New(4, 5))
+	var dimErr *matrix.DimensionError
+	if !errors.As(err, &dimErr) {
+		t.Fatalf("expected a *DimensionError, instead got %T: %v", err, err)
+	}
+	if dimErr.First != [2]int{2, 3} || dimErr.Second != [2]int{4, 5} {
+		t.Fatalf("expected the dimensions 2x3 and 4x5, instead got %v and %v", dimErr.First, dimErr.Second)
+	}
+
+	// The panicking versions panic with the same errors.
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.As(err, &dimErr) {
+			t.Fatalf("expected Add to panic with a *DimensionError, instead got %v", err)
+		}
+	}()
+	matrix.Add(matrix.New(2, 3), matrix.New(3, 2))
+}
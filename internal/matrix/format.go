@@ -0,0 +1,145 @@
+package matrix
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FormatOption configures how Sprint and Fprint format a matrix.
+type FormatOption func(*formatOptions)
+
+type formatOptions struct {
+	precision        int
+	align            bool
+	maxRows, maxCols int
+}
+
+const (
+	// defaultMaxRows and defaultMaxCols are the most rows and cols Sprint
+	// prints before eliding the middle ones, enough to see the shape of the
+	// data without flooding a terminal.
+	defaultMaxRows = 10
+	defaultMaxCols = 10
+	// elision replaces the rows and cols that aren't printed.
+	elision = "..."
+)
+
+// WithPrecision prints every entry with the given number of digits after
+// the decimal point. A negative precision prints the fewest digits that
+// represent each entry exactly, which is the default.
+func WithPrecision(digits int) FormatOption {
+	return func(o *formatOptions) {
+		o.precision = digits
+	}
+}
+
+// WithAlignment sets whether the entries are padded so that the columns
+// line up, with numbers aligned on the right. Alignment is on by default.
+func WithAlignment(align bool) FormatOption {
+	return func(o *formatOptions) {
+		o.align = align
+	}
+}
+
+// WithElision sets the most rows and cols that are printed. A larger matrix
+// has its first and last rows and cols printed with "..." in place of the
+// ones in between. A limit of 0 or less prints every row or col. The
+// default is 10 of each.
+func WithElision(maxRows, maxCols int) FormatOption {
+	return func(o *formatOptions) {
+		o.maxRows = maxRows
+		o.maxCols = maxCols
+	}
+}
+
+// shown returns the indices out of length that are printed when at most max
+// are, with -1 standing for the elided ones.
+func shown(length, max int) []int {
+	if max <= 0 || length <= max {
+		max = length
+	}
+	head := (max + 1) / 2
+	result := make([]int, 0, max+1)
+	for i := 0; i < head; i++ {
+		result = append(result, i)
+	}
+	if max < length {
+		result = append(result, -1)
+	}
+	for i := length - (max - head); i < length; i++ {
+		result = append(result, i)
+	}
+	return result
+}
+
+// Sprint formats a matrix for reading, with one row per line. By default the
+// columns are aligned and a matrix with more than 10 rows or cols has the
+// middle ones elided, so that printing a large matrix such as the weights
+// of a layer doesn't flood the terminal. Options change the precision,
+// alignment and elision.
+//
+// Without elision or a fixed precision the output can be read back by
+// ParseDense.
+func Sprint[T Float](m *Dense[T], options ...FormatOption) string {
+	o := formatOptions{precision: -1, align: true, maxRows: defaultMaxRows, maxCols: defaultMaxCols}
+	for _, option := range options {
+		option(&o)
+	}
+	bitSize := 64
+	if isFloat32[T]() {
+		bitSize = 32
+	}
+
+	rows, cols := shown(m.rows, o.maxRows), shown(m.cols, o.maxCols)
+	cells := make([][]string, len(rows))
+	widths := make([]int, len(cols))
+	for i, r := range rows {
+		cells[i] = make([]string, len(cols))
+		for j, c := range cols {
+			cell := elision
+			if r != -1 && c != -1 {
+				cell = formatEntry(float64(m.Get(r, c)), o.precision, bitSize)
+			}
+			cells[i][j] = cell
+			if len(cell) > widths[j] {
+				widths[j] = len(cell)
+			}
+		}
+	}
+
+	sb := strings.Builder{}
+	for i, row := range cells {
+		if i != 0 {
+			sb.WriteRune('\n')
+		}
+		if rows[i] == -1 && !o.align {
+			sb.WriteString(elision)
+			continue
+		}
+		for j, cell := range row {
+			if j != 0 {
+				sb.WriteRune(' ')
+			}
+			if o.align {
+				sb.WriteString(strings.Repeat(" ", widths[j]-len(cell)))
+			}
+			sb.WriteString(cell)
+		}
+	}
+	return sb.String()
+}
+
+func formatEntry(x float64, precision, bitSize int) string {
+	if precision < 0 {
+		// Match fmt.Sprint, which String uses.
+		return strconv.FormatFloat(x, 'g', -1, bitSize)
+	}
+	return strconv.FormatFloat(x, 'f', precision, bitSize)
+}
+
+// Fprint writes a matrix to w formatted by Sprint, followed by a newline.
+func Fprint[T Float](w io.Writer, m *Dense[T], options ...FormatOption) error {
+	_, err := io.WriteString(w, Sprint(m, options...)+"\n")
+	return err
+}
@@ -0,0 +1,72 @@
+package matrix_test
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestSprint(t *testing.T) {
+	m := matrix.Parse("1 -2.5 300\n0.125 10 -6")
+	tests := []struct {
+		name     string
+		options  []matrix.FormatOption
+		expected string
+	}{
+		{"Default", nil, "    1 -2.5 300\n0.125   10  -6"},
+		{"Precision", []matrix.FormatOption{matrix.WithPrecision(2)}, "1.00 -2.50 300.00\n0.12 10.00  -6.00"},
+		{"No alignment", []matrix.FormatOption{matrix.WithAlignment(false)}, "1 -2.5 300\n0.125 10 -6"},
+		{"Elide cols", []matrix.FormatOption{matrix.WithElision(0, 2)}, "    1 ... 300\n0.125 ...  -6"},
+		{"Elide rows", []matrix.FormatOption{matrix.WithElision(1, 0)}, "  1 -2.5 300\n...  ... ..."},
+		{
+			"Elide without alignment",
+			[]matrix.FormatOption{matrix.WithElision(1, 2), matrix.WithAlignment(false)},
+			"1 ... 300\n...",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := matrix.Sprint(m, test.options...); result != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestSprintLarge(t *testing.T) {
+	m := matrix.NewRandomNormalFrom(rand.New(rand.NewSource(1)), 784, 100)
+	lines := strings.Split(matrix.Sprint(m, matrix.WithPrecision(3)), "\n")
+	if len(lines) != 11 {
+		t.Fatalf("expected 10 rows and an elided one, instead there were %d lines", len(lines))
+	}
+	if fields := strings.Fields(lines[5]); len(fields) != 11 || strings.Join(fields, "") != strings.Repeat("...", 11) {
+		t.Fatalf("expected the middle line to be elided, instead it was %q", lines[5])
+	}
+	for _, line := range []string{lines[0], lines[10]} {
+		if fields := strings.Fields(line); len(fields) != 11 || fields[5] != "..." {
+			t.Fatalf("expected 10 cols and an elided one, instead the line was %q", line)
+		}
+	}
+}
+
+func TestSprintRoundTrip(t *testing.T) {
+	m := matrix.NewRandomNormalFrom(rand.New(rand.NewSource(1)), 12, 3)
+	text := matrix.Sprint(m, matrix.WithElision(0, 0))
+	if result := matrix.Parse(text); !matrix.Equal(result, m) {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", m, result)
+	}
+	if result := matrix.Sprint(m, matrix.WithElision(0, 0), matrix.WithAlignment(false)); result != m.String() {
+		t.Fatalf("expected the same output as String:\n\n%s\n\ninstead got:\n\n%s", m, result)
+	}
+
+	var buffer bytes.Buffer
+	if err := matrix.Fprint(&buffer, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := matrix.Sprint(m) + "\n"; buffer.String() != expected {
+		t.Fatalf("expected Fprint to write:\n\n%s\n\ninstead it wrote:\n\n%s", expected, buffer.String())
+	}
+}
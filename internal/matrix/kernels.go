@@ -0,0 +1,71 @@
+package matrix
+
+// The kernels below work on raw slices and are the building blocks of the
+// OptimizedEngine. These are the pure Go versions, which are used on every
+// platform that doesn't have an assembly version of a kernel (see
+// kernels_amd64.go). Their loops are unrolled into four independent sums or
+// updates so that the arithmetic can be pipelined.
+
+// dotGeneric returns the dot product of x and y, which must be at least as
+// long as x.
+func dotGeneric[T Float](x, y []T) T {
+	y = y[:len(x)]
+	var s0, s1, s2, s3 T
+	i := 0
+	for ; i <= len(x)-4; i += 4 {
+		s0 += x[i] * y[i]
+		s1 += x[i+1] * y[i+1]
+		s2 += x[i+2] * y[i+2]
+		s3 += x[i+3] * y[i+3]
+	}
+	for ; i < len(x); i++ {
+		s0 += x[i] * y[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+// axpyGeneric computes y += alpha*x. y must be at least as long as x.
+func axpyGeneric[T Float](alpha T, x, y []T) {
+	y = y[:len(x)]
+	i := 0
+	for ; i <= len(x)-4; i += 4 {
+		y[i] += alpha * x[i]
+		y[i+1] += alpha * x[i+1]
+		y[i+2] += alpha * x[i+2]
+		y[i+3] += alpha * x[i+3]
+	}
+	for ; i < len(x); i++ {
+		y[i] += alpha * x[i]
+	}
+}
+
+// scaleGeneric computes dst = alpha*x. dst must be at least as long as x.
+func scaleGeneric[T Float](dst []T, alpha T, x []T) {
+	dst = dst[:len(x)]
+	i := 0
+	for ; i <= len(x)-4; i += 4 {
+		dst[i] = alpha * x[i]
+		dst[i+1] = alpha * x[i+1]
+		dst[i+2] = alpha * x[i+2]
+		dst[i+3] = alpha * x[i+3]
+	}
+	for ; i < len(x); i++ {
+		dst[i] = alpha * x[i]
+	}
+}
+
+// sum returns the sum of the entries of x.
+func sum[T Float](x []T) T {
+	var s0, s1, s2, s3 T
+	i := 0
+	for ; i <= len(x)-4; i += 4 {
+		s0 += x[i]
+		s1 += x[i+1]
+		s2 += x[i+2]
+		s3 += x[i+3]
+	}
+	for ; i < len(x); i++ {
+		s0 += x[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
@@ -0,0 +1,107 @@
+//go:build amd64 && !purego
+
+package matrix
+
+import "unsafe"
+
+// useAVX2 reports whether the CPU and operating system support the AVX2 and
+// FMA instructions used by the assembly kernels. When they don't, the pure Go
+// kernels are used instead. Building with the purego tag disables the
+// assembly kernels entirely.
+var useAVX2 = hasAVX2AndFMA()
+
+func hasAVX2AndFMA() bool {
+	const (
+		fmaBit     = 1 << 12 // CPUID.1:ECX
+		osxsaveBit = 1 << 27 // CPUID.1:ECX
+		avxBit     = 1 << 28 // CPUID.1:ECX
+		avx2Bit    = 1 << 5  // CPUID.(7,0):EBX
+		// The operating system must save the XMM and YMM registers when
+		// switching between threads.
+		xmmYmmState = 1<<1 | 1<<2
+	)
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	if ecx1&fmaBit == 0 || ecx1&osxsaveBit == 0 || ecx1&avxBit == 0 {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&xmmYmmState != xmmYmmState {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&avx2Bit != 0
+}
+
+// cpuid executes the CPUID instruction with the given leaf and subleaf.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv reads the XCR0 register.
+func xgetbv() (eax, edx uint32)
+
+// The AVX2 kernels behave like their pure Go counterparts, except that the
+// lengths of their slices must already have been made equal. Each has a
+// float64 and a float32 version.
+
+//go:noescape
+func dotAVX2(x, y []float64) float64
+
+//go:noescape
+func axpyAVX2(alpha float64, x, y []float64)
+
+//go:noescape
+func scaleAVX2(dst []float64, alpha float64, x []float64)
+
+//go:noescape
+func dotAVX2F32(x, y []float32) float32
+
+//go:noescape
+func axpyAVX2F32(alpha float32, x, y []float32)
+
+//go:noescape
+func scaleAVX2F32(dst []float32, alpha float32, x []float32)
+
+// float32s and float64s view a slice of T as the type that T really is.
+// Float has no approximate elements, so T is always exactly one of them.
+
+func float32s[T Float](x []T) []float32 {
+	return *(*[]float32)(unsafe.Pointer(&x))
+}
+
+func float64s[T Float](x []T) []float64 {
+	return *(*[]float64)(unsafe.Pointer(&x))
+}
+
+func dot[T Float](x, y []T) T {
+	switch {
+	case !useAVX2:
+		return dotGeneric(x, y)
+	case isFloat32[T]():
+		return T(dotAVX2F32(float32s(x), float32s(y[:len(x)])))
+	}
+	return T(dotAVX2(float64s(x), float64s(y[:len(x)])))
+}
+
+func axpy[T Float](alpha T, x, y []T) {
+	switch {
+	case !useAVX2:
+		axpyGeneric(alpha, x, y)
+	case isFloat32[T]():
+		axpyAVX2F32(float32(alpha), float32s(x), float32s(y[:len(x)]))
+	default:
+		axpyAVX2(float64(alpha), float64s(x), float64s(y[:len(x)]))
+	}
+}
+
+func scale[T Float](dst []T, alpha T, x []T) {
+	switch {
+	case !useAVX2:
+		scaleGeneric(dst, alpha, x)
+	case isFloat32[T]():
+		scaleAVX2F32(float32s(dst[:len(x)]), float32(alpha), float32s(x))
+	default:
+		scaleAVX2(float64s(dst[:len(x)]), float64(alpha), float64s(x))
+	}
+}
@@ -0,0 +1,353 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// func dotAVX2(x, y []float64) float64
+//
+// Sums 16 products per iteration into four independent accumulators, then 4
+// at a time, then one at a time.
+TEXT ·dotAVX2(SB), NOSPLIT, $0-56
+	MOVQ x_base+0(FP), SI
+	MOVQ x_len+8(FP), CX
+	MOVQ y_base+24(FP), DI
+	VXORPD Y0, Y0, Y0
+	VXORPD Y1, Y1, Y1
+	VXORPD Y2, Y2, Y2
+	VXORPD Y3, Y3, Y3
+	CMPQ CX, $16
+	JL dot_loop4
+
+dot_loop16:
+	VMOVUPD 0(SI), Y4
+	VMOVUPD 32(SI), Y5
+	VMOVUPD 64(SI), Y6
+	VMOVUPD 96(SI), Y7
+	VFMADD231PD 0(DI), Y4, Y0
+	VFMADD231PD 32(DI), Y5, Y1
+	VFMADD231PD 64(DI), Y6, Y2
+	VFMADD231PD 96(DI), Y7, Y3
+	ADDQ $128, SI
+	ADDQ $128, DI
+	SUBQ $16, CX
+	CMPQ CX, $16
+	JGE dot_loop16
+
+dot_loop4:
+	CMPQ CX, $4
+	JL dot_reduce
+	VMOVUPD 0(SI), Y4
+	VFMADD231PD 0(DI), Y4, Y0
+	ADDQ $32, SI
+	ADDQ $32, DI
+	SUBQ $4, CX
+	JMP dot_loop4
+
+dot_reduce:
+	VADDPD Y1, Y0, Y0
+	VADDPD Y3, Y2, Y2
+	VADDPD Y2, Y0, Y0
+	VEXTRACTF128 $1, Y0, X1
+	VADDPD X1, X0, X0
+	VHADDPD X0, X0, X0
+
+dot_loop1:
+	TESTQ CX, CX
+	JE dot_done
+	VMOVSD 0(SI), X1
+	VFMADD231SD 0(DI), X1, X0
+	ADDQ $8, SI
+	ADDQ $8, DI
+	DECQ CX
+	JMP dot_loop1
+
+dot_done:
+	VZEROUPPER
+	MOVSD X0, ret+48(FP)
+	RET
+
+// func axpyAVX2(alpha float64, x, y []float64)
+TEXT ·axpyAVX2(SB), NOSPLIT, $0-56
+	VBROADCASTSD alpha+0(FP), Y0
+	MOVQ x_base+8(FP), SI
+	MOVQ x_len+16(FP), CX
+	MOVQ y_base+32(FP), DI
+	CMPQ CX, $16
+	JL axpy_loop4
+
+axpy_loop16:
+	VMOVUPD 0(DI), Y1
+	VMOVUPD 32(DI), Y2
+	VMOVUPD 64(DI), Y3
+	VMOVUPD 96(DI), Y4
+	VFMADD231PD 0(SI), Y0, Y1
+	VFMADD231PD 32(SI), Y0, Y2
+	VFMADD231PD 64(SI), Y0, Y3
+	VFMADD231PD 96(SI), Y0, Y4
+	VMOVUPD Y1, 0(DI)
+	VMOVUPD Y2, 32(DI)
+	VMOVUPD Y3, 64(DI)
+	VMOVUPD Y4, 96(DI)
+	ADDQ $128, SI
+	ADDQ $128, DI
+	SUBQ $16, CX
+	CMPQ CX, $16
+	JGE axpy_loop16
+
+axpy_loop4:
+	CMPQ CX, $4
+	JL axpy_loop1
+	VMOVUPD 0(DI), Y1
+	VFMADD231PD 0(SI), Y0, Y1
+	VMOVUPD Y1, 0(DI)
+	ADDQ $32, SI
+	ADDQ $32, DI
+	SUBQ $4, CX
+	JMP axpy_loop4
+
+axpy_loop1:
+	TESTQ CX, CX
+	JE axpy_done
+	VMOVSD 0(DI), X1
+	VFMADD231SD 0(SI), X0, X1
+	VMOVSD X1, 0(DI)
+	ADDQ $8, SI
+	ADDQ $8, DI
+	DECQ CX
+	JMP axpy_loop1
+
+axpy_done:
+	VZEROUPPER
+	RET
+
+// func scaleAVX2(dst []float64, alpha float64, x []float64)
+TEXT ·scaleAVX2(SB), NOSPLIT, $0-56
+	MOVQ dst_base+0(FP), DI
+	VBROADCASTSD alpha+24(FP), Y0
+	MOVQ x_base+32(FP), SI
+	MOVQ x_len+40(FP), CX
+	CMPQ CX, $16
+	JL scale_loop4
+
+scale_loop16:
+	VMULPD 0(SI), Y0, Y1
+	VMULPD 32(SI), Y0, Y2
+	VMULPD 64(SI), Y0, Y3
+	VMULPD 96(SI), Y0, Y4
+	VMOVUPD Y1, 0(DI)
+	VMOVUPD Y2, 32(DI)
+	VMOVUPD Y3, 64(DI)
+	VMOVUPD Y4, 96(DI)
+	ADDQ $128, SI
+	ADDQ $128, DI
+	SUBQ $16, CX
+	CMPQ CX, $16
+	JGE scale_loop16
+
+scale_loop4:
+	CMPQ CX, $4
+	JL scale_loop1
+	VMULPD 0(SI), Y0, Y1
+	VMOVUPD Y1, 0(DI)
+	ADDQ $32, SI
+	ADDQ $32, DI
+	SUBQ $4, CX
+	JMP scale_loop4
+
+scale_loop1:
+	TESTQ CX, CX
+	JE scale_done
+	VMULSD 0(SI), X0, X1
+	VMOVSD X1, 0(DI)
+	ADDQ $8, SI
+	ADDQ $8, DI
+	DECQ CX
+	JMP scale_loop1
+
+scale_done:
+	VZEROUPPER
+	RET
+
+// func dotAVX2F32(x, y []float32) float32
+//
+// Sums 32 products per iteration into four independent accumulators, then 8
+// at a time, then one at a time.
+TEXT ·dotAVX2F32(SB), NOSPLIT, $0-52
+	MOVQ x_base+0(FP), SI
+	MOVQ x_len+8(FP), CX
+	MOVQ y_base+24(FP), DI
+	VXORPS Y0, Y0, Y0
+	VXORPS Y1, Y1, Y1
+	VXORPS Y2, Y2, Y2
+	VXORPS Y3, Y3, Y3
+	CMPQ CX, $32
+	JL dotf32_loop8
+
+dotf32_loop32:
+	VMOVUPS 0(SI), Y4
+	VMOVUPS 32(SI), Y5
+	VMOVUPS 64(SI), Y6
+	VMOVUPS 96(SI), Y7
+	VFMADD231PS 0(DI), Y4, Y0
+	VFMADD231PS 32(DI), Y5, Y1
+	VFMADD231PS 64(DI), Y6, Y2
+	VFMADD231PS 96(DI), Y7, Y3
+	ADDQ $128, SI
+	ADDQ $128, DI
+	SUBQ $32, CX
+	CMPQ CX, $32
+	JGE dotf32_loop32
+
+dotf32_loop8:
+	CMPQ CX, $8
+	JL dotf32_reduce
+	VMOVUPS 0(SI), Y4
+	VFMADD231PS 0(DI), Y4, Y0
+	ADDQ $32, SI
+	ADDQ $32, DI
+	SUBQ $8, CX
+	JMP dotf32_loop8
+
+dotf32_reduce:
+	VADDPS Y1, Y0, Y0
+	VADDPS Y3, Y2, Y2
+	VADDPS Y2, Y0, Y0
+	VEXTRACTF128 $1, Y0, X1
+	VADDPS X1, X0, X0
+	VHADDPS X0, X0, X0
+	VHADDPS X0, X0, X0
+
+dotf32_loop1:
+	TESTQ CX, CX
+	JE dotf32_done
+	VMOVSS 0(SI), X1
+	VFMADD231SS 0(DI), X1, X0
+	ADDQ $4, SI
+	ADDQ $4, DI
+	DECQ CX
+	JMP dotf32_loop1
+
+dotf32_done:
+	VZEROUPPER
+	MOVSS X0, ret+48(FP)
+	RET
+
+// func axpyAVX2F32(alpha float32, x, y []float32)
+TEXT ·axpyAVX2F32(SB), NOSPLIT, $0-56
+	VBROADCASTSS alpha+0(FP), Y0
+	MOVQ x_base+8(FP), SI
+	MOVQ x_len+16(FP), CX
+	MOVQ y_base+32(FP), DI
+	CMPQ CX, $32
+	JL axpyf32_loop8
+
+axpyf32_loop32:
+	VMOVUPS 0(DI), Y1
+	VMOVUPS 32(DI), Y2
+	VMOVUPS 64(DI), Y3
+	VMOVUPS 96(DI), Y4
+	VFMADD231PS 0(SI), Y0, Y1
+	VFMADD231PS 32(SI), Y0, Y2
+	VFMADD231PS 64(SI), Y0, Y3
+	VFMADD231PS 96(SI), Y0, Y4
+	VMOVUPS Y1, 0(DI)
+	VMOVUPS Y2, 32(DI)
+	VMOVUPS Y3, 64(DI)
+	VMOVUPS Y4, 96(DI)
+	ADDQ $128, SI
+	ADDQ $128, DI
+	SUBQ $32, CX
+	CMPQ CX, $32
+	JGE axpyf32_loop32
+
+axpyf32_loop8:
+	CMPQ CX, $8
+	JL axpyf32_loop1
+	VMOVUPS 0(DI), Y1
+	VFMADD231PS 0(SI), Y0, Y1
+	VMOVUPS Y1, 0(DI)
+	ADDQ $32, SI
+	ADDQ $32, DI
+	SUBQ $8, CX
+	JMP axpyf32_loop8
+
+axpyf32_loop1:
+	TESTQ CX, CX
+	JE axpyf32_done
+	VMOVSS 0(DI), X1
+	VFMADD231SS 0(SI), X0, X1
+	VMOVSS X1, 0(DI)
+	ADDQ $4, SI
+	ADDQ $4, DI
+	DECQ CX
+	JMP axpyf32_loop1
+
+axpyf32_done:
+	VZEROUPPER
+	RET
+
+// func scaleAVX2F32(dst []float32, alpha float32, x []float32)
+TEXT ·scaleAVX2F32(SB), NOSPLIT, $0-56
+	MOVQ dst_base+0(FP), DI
+	VBROADCASTSS alpha+24(FP), Y0
+	MOVQ x_base+32(FP), SI
+	MOVQ x_len+40(FP), CX
+	CMPQ CX, $32
+	JL scalef32_loop8
+
+scalef32_loop32:
+	VMULPS 0(SI), Y0, Y1
+	VMULPS 32(SI), Y0, Y2
+	VMULPS 64(SI), Y0, Y3
+	VMULPS 96(SI), Y0, Y4
+	VMOVUPS Y1, 0(DI)
+	VMOVUPS Y2, 32(DI)
+	VMOVUPS Y3, 64(DI)
+	VMOVUPS Y4, 96(DI)
+	ADDQ $128, SI
+	ADDQ $128, DI
+	SUBQ $32, CX
+	CMPQ CX, $32
+	JGE scalef32_loop32
+
+scalef32_loop8:
+	CMPQ CX, $8
+	JL scalef32_loop1
+	VMULPS 0(SI), Y0, Y1
+	VMOVUPS Y1, 0(DI)
+	ADDQ $32, SI
+	ADDQ $32, DI
+	SUBQ $8, CX
+	JMP scalef32_loop8
+
+scalef32_loop1:
+	TESTQ CX, CX
+	JE scalef32_done
+	VMULSS 0(SI), X0, X1
+	VMOVSS X1, 0(DI)
+	ADDQ $4, SI
+	ADDQ $4, DI
+	DECQ CX
+	JMP scalef32_loop1
+
+scalef32_done:
+	VZEROUPPER
+	RET
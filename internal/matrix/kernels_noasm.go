@@ -0,0 +1,15 @@
+//go:build !amd64 || purego
+
+package matrix
+
+func dot[T Float](x, y []T) T {
+	return dotGeneric(x, y)
+}
+
+func axpy[T Float](alpha T, x, y []T) {
+	axpyGeneric(alpha, x, y)
+}
+
+func scale[T Float](dst []T, alpha T, x []T) {
+	scaleGeneric(dst, alpha, x)
+}
@@ -0,0 +1,89 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func kernelTestSlices[T Float](length, offset int) (x, y []T) {
+	x = make([]T, length+offset)
+	y = make([]T, length+offset)
+	for i := range x {
+		x[i] = T(random.NormFloat64())
+		y[i] = T(random.NormFloat64())
+	}
+	// Offsetting the start of the slices tests unaligned loads and stores.
+	return x[offset:], y[offset:]
+}
+
+func kernelsClose[T Float](expected, result T) bool {
+	tolerance := 100 * epsilon[T]()
+	difference := math.Abs(float64(expected - result))
+	return difference <= tolerance*math.Max(1, math.Abs(float64(expected)))
+}
+
+func TestKernels(t *testing.T) {
+	t.Run("float64", testKernels[float64])
+	t.Run("float32", testKernels[float32])
+}
+
+func testKernels[T Float](t *testing.T) {
+	for length := 0; length <= 70; length++ {
+		for offset := 0; offset < 3; offset++ {
+			name := fmt.Sprintf("Length %d Offset %d", length, offset)
+			t.Run(name, func(t *testing.T) {
+				x, y := kernelTestSlices[T](length, offset)
+
+				expectedDot, resultDot := dotGeneric(x, y), dot(x, y)
+				if !kernelsClose(expectedDot, resultDot) {
+					t.Fatalf("expected dot to return %f, instead it returned %f", expectedDot, resultDot)
+				}
+
+				expected := append([]T(nil), y...)
+				result := append([]T(nil), y...)
+				axpyGeneric(1.5, x, expected)
+				axpy(1.5, x, result)
+				for i := range expected {
+					if !kernelsClose(expected[i], result[i]) {
+						t.Fatalf("expected axpy to set index %d to %f, instead it was %f", i, expected[i], result[i])
+					}
+				}
+
+				scaleGeneric(expected, -0.5, x)
+				scale(result, -0.5, x)
+				for i := range expected {
+					if expected[i] != result[i] {
+						t.Fatalf("expected scale to set index %d to %f, instead it was %f", i, expected[i], result[i])
+					}
+				}
+			})
+		}
+	}
+
+	t.Run("Longer second slice", func(t *testing.T) {
+		x, y := kernelTestSlices[T](10, 0)
+		y = append(y, 100)
+		if expected, result := dotGeneric(x, y), dot(x, y); !kernelsClose(expected, result) {
+			t.Fatalf("expected dot to ignore the extra entry and return %f, instead it returned %f", expected, result)
+		}
+		axpy(2, x, y)
+		if y[10] != 100 {
+			t.Fatalf("expected axpy not to touch entries beyond the length of x")
+		}
+	})
+}
+
+func BenchmarkDot(b *testing.B) {
+	x, y := kernelTestSlices[float64](784, 0)
+	b.Run("Kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dot(x, y)
+		}
+	})
+	b.Run("Generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dotGeneric(x, y)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package matrix
+
+// LU is the LU decomposition of a square matrix A with partial pivoting:
+// P * A = L * U, where P is a permutation matrix, L is lower triangular with
+// ones on its diagonal and U is upper triangular.
+//
+// A singular matrix still has an LU decomposition, so NewLU succeeds for
+// one. Its determinant is 0, and Solve and Inverse return ErrSingular.
+type LU[T Float] struct {
+	// lu holds L below its diagonal and U on and above it.
+	lu       *Dense[T]
+	pivot    []int
+	sign     T
+	singular bool
+}
+
+// NewLU calculates the LU decomposition of a square matrix. The error wraps
+// ErrNotSquare if the matrix is not square.
+func NewLU[T Float](a *Dense[T]) (*LU[T], error) {
+	if err := squareErr(a); err != nil {
+		return nil, err
+	}
+	n := a.rows
+	lu := a.Clone()
+	pivot := make([]int, n)
+	for i := range pivot {
+		pivot[i] = i
+	}
+	var sign T = 1
+	tolerance := singularTolerance(n, vectorNorm(a, Infinity))
+	singular := false
+	for k := 0; k < n; k++ {
+		// Partial pivoting: swap the row with the largest entry in this
+		// column into place, so that every multiplier is at most 1.
+		p := k
+		for i := k + 1; i < n; i++ {
+			if abs(lu.Get(i, k)) > abs(lu.Get(p, k)) {
+				p = i
+			}
+		}
+		if p != k {
+			for c := 0; c < n; c++ {
+				x, y := lu.Get(k, c), lu.Get(p, c)
+				lu.Set(k, c, y)
+				lu.Set(p, c, x)
+			}
+			pivot[k], pivot[p] = pivot[p], pivot[k]
+			sign = -sign
+		}
+
+		diagonal := lu.Get(k, k)
+		if abs(diagonal) <= tolerance {
+			singular = true
+		}
+		if diagonal == 0 || k+1 == n {
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			multiplier := lu.Get(i, k) / diagonal
+			lu.Set(i, k, multiplier)
+			if multiplier != 0 {
+				AddScaled(lu.Slice(i, i+1, k+1, n), -multiplier, lu.Slice(k, k+1, k+1, n))
+			}
+		}
+	}
+	return &LU[T]{lu: lu, pivot: pivot, sign: sign, singular: singular}, nil
+}
+
+// L returns the unit lower triangular factor.
+func (d *LU[T]) L() *Dense[T] {
+	n := d.lu.rows
+	result := NewDense[T](n, n)
+	for r := 0; r < n; r++ {
+		for c := 0; c < r; c++ {
+			result.Set(r, c, d.lu.Get(r, c))
+		}
+		result.Set(r, r, 1)
+	}
+	return result
+}
+
+// U returns the upper triangular factor.
+func (d *LU[T]) U() *Dense[T] {
+	n := d.lu.rows
+	result := NewDense[T](n, n)
+	for r := 0; r < n; r++ {
+		for c := r; c < n; c++ {
+			result.Set(r, c, d.lu.Get(r, c))
+		}
+	}
+	return result
+}
+
+// Pivot returns the row permutation: row i of P * A is row Pivot()[i] of A.
+func (d *LU[T]) Pivot() []int {
+	result := make([]int, len(d.pivot))
+	copy(result, d.pivot)
+	return result
+}
+
+// P returns the permutation matrix.
+func (d *LU[T]) P() *Dense[T] {
+	n := len(d.pivot)
+	result := NewDense[T](n, n)
+	for i, p := range d.pivot {
+		result.Set(i, p, 1)
+	}
+	return result
+}
+
+// Determinant returns the determinant of the decomposed matrix.
+func (d *LU[T]) Determinant() T {
+	result := d.sign
+	for i := 0; i < d.lu.rows; i++ {
+		result *= d.lu.Get(i, i)
+	}
+	return result
+}
+
+// Solve returns the matrix x that satisfies A * x = b. The error wraps
+// ErrSingular if A is singular, or is a *DimensionError if b doesn't have as
+// many rows as A.
+func (d *LU[T]) Solve(b *Dense[T]) (*Dense[T], error) {
+	if err := rhsErr(d.lu, b); err != nil {
+		return nil, err
+	}
+	if d.singular {
+		return nil, ErrSingular
+	}
+	x := NewDense[T](b.rows, b.cols)
+	for i, p := range d.pivot {
+		Copy(x.RowView(i), b.RowView(p))
+	}
+	solveLower(d.lu, x, true)
+	solveUpper(d.lu, x)
+	return x, nil
+}
+
+// Inverse returns the inverse of the decomposed matrix. The error wraps
+// ErrSingular if it is singular.
+func (d *LU[T]) Inverse() (*Dense[T], error) {
+	return d.Solve(Identity[T](d.lu.rows))
+}
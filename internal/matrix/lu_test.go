@@ -0,0 +1,66 @@
+package matrix_test
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestLU(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *matrix.Matrix
+	}{
+		{"2x2", matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2)},
+		{"Needs pivoting", matrix.NewFromSlice([]float64{0, 1, 2, 1, 0, 3, 4, -3, 8}, 3, 3)},
+		{"Random", matrix.NewRandomNormalFrom(rand.New(rand.NewSource(1)), 8, 8)},
+		{"Transposed", matrix.NewRandomNormalFrom(rand.New(rand.NewSource(2)), 5, 5).Transpose()},
+		{"Singular", matrix.NewFromSlice([]float64{1, 2, 2, 4}, 2, 2)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lu, err := matrix.NewLU(test.a)
+			if err != nil {
+				t.Fatalf("expected NewLU to succeed, instead got %v", err)
+			}
+			pa := matrix.Multiply(lu.P(), test.a)
+			product := matrix.Multiply(lu.L(), lu.U())
+			if !matrix.EqualApprox(pa, product, 1e-12, 1e-12) {
+				t.Fatalf("expected P * A to equal L * U, instead %v", matrix.Diff(pa, product))
+			}
+			l, u := lu.L(), lu.U()
+			n, _ := l.Dimensions()
+			for r := 0; r < n; r++ {
+				if l.Get(r, r) != 1 {
+					t.Fatalf("expected L to have ones on its diagonal, instead got:\n%s", l)
+				}
+				for c := r + 1; c < n; c++ {
+					if l.Get(r, c) != 0 || u.Get(c, r) != 0 {
+						t.Fatalf("expected triangular factors, instead got:\n%s\n\nand\n\n%s", l, u)
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := matrix.NewLU(matrix.New(2, 3)); !errors.Is(err, matrix.ErrNotSquare) {
+		t.Fatalf("expected NewLU to return ErrNotSquare for a 2x3 matrix, instead got %v", err)
+	}
+}
+
+func TestLUPivot(t *testing.T) {
+	a := matrix.NewFromSlice([]float64{0, 0, 1, 0, 2, 0, 3, 0, 0}, 3, 3)
+	lu, err := matrix.NewLU(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pivot := lu.Pivot(); !reflect.DeepEqual(pivot, []int{2, 1, 0}) {
+		t.Fatalf("expected the pivot [2 1 0], instead got %v", pivot)
+	}
+	if det := lu.Determinant(); det != -6 {
+		t.Fatalf("expected a determinant of -6, instead got %f", det)
+	}
+}
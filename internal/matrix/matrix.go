@@ -5,208 +5,883 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync"
 )
 
 const seed = 0
 
 var (
-	random = rand.New(rand.NewSource(seed))
+	// random is shared by every call to NewRandomNormal, so its source is
+	// locked to make it safe for concurrent use.
+	random = rand.New(&lockedSource{src: rand.NewSource(seed).(rand.Source64)})
 )
 
-// Matrix is a basic implementation of a matrix.
-type Matrix struct {
-	rows, cols int
-	data       []float64
-	transpose  *Matrix
+// lockedSource is a rand.Source that is safe for concurrent use.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
 }
 
-func (m *Matrix) accessCheck(row, col int) {
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// Float is the set of types that a matrix can hold.
+type Float interface {
+	float32 | float64
+}
+
+// Dense is a basic implementation of a matrix that stores every entry.
+//
+// The entry at (row, col) is stored at data[row*rowStride+col*colStride].
+// Matrices created by New are laid out in row-major order, but other layouts
+// let views such as a transpose share the data of the matrix they came from.
+type Dense[T Float] struct {
+	rows, cols           int
+	rowStride, colStride int
+	data                 []T
+}
+
+func isFloat32[T Float]() bool {
+	var zero T
+	_, ok := any(zero).(float32)
+	return ok
+}
+
+// epsilon returns the difference between 1 and the next value of type T
+// after it.
+func epsilon[T Float]() float64 {
+	if isFloat32[T]() {
+		return 0x1p-23
+	}
+	return 0x1p-52
+}
+
+// Matrix is a matrix of float64s.
+type Matrix = Dense[float64]
+
+// Matrix32 is a matrix of float32s, which takes half the memory of a Matrix.
+type Matrix32 = Dense[float32]
+
+func (m *Dense[T]) index(row, col int) int {
+	return row*m.rowStride + col*m.colStride
+}
+
+func (m *Dense[T]) accessErr(row, col int) error {
 	if row < 0 || col < 0 {
-		err := fmt.Errorf(
-			"matrix: row and col (%d, %d) cannot be less than 0",
+		return fmt.Errorf(
+			"%w: row and col (%d, %d) cannot be less than 0",
+			ErrOutOfRange,
 			row,
 			col,
 		)
-		panic(err)
 	}
 	if row >= m.rows || col >= m.cols {
-		err := fmt.Errorf(
-			"matrix: row and col (%d, %d) "+
+		return fmt.Errorf(
+			"%w: row and col (%d, %d) "+
 				"cannot be greater than or equal to the matrix's dimensions (%dx%d)",
+			ErrOutOfRange,
 			row,
 			col,
 			m.rows,
 			m.cols,
 		)
+	}
+	return nil
+}
+
+func (m *Dense[T]) accessCheck(row, col int) {
+	if err := m.accessErr(row, col); err != nil {
 		panic(err)
 	}
 }
 
-// Get returns the float64 value at the given row and column
-func (m *Matrix) Get(row, col int) float64 {
+// Get returns the value at the given row and column
+func (m *Dense[T]) Get(row, col int) T {
 	m.accessCheck(row, col)
-	return m.data[m.cols*row+col]
+	return m.data[m.index(row, col)]
 }
 
 // Dimensions returns the number of rows and columns a matrix has
-func (m *Matrix) Dimensions() (rows, cols int) {
+func (m *Dense[T]) Dimensions() (rows, cols int) {
 	return m.rows, m.cols
 }
 
-func (m *Matrix) set(row, col int, value float64) {
+// Set sets the value at the given row and column
+func (m *Dense[T]) Set(row, col int, value T) {
 	m.accessCheck(row, col)
-	m.data[m.cols*row+col] = value
+	m.data[m.index(row, col)] = value
 }
 
-func (m *Matrix) String() string {
-	writeRow := func(row []float64, sb *strings.Builder) {
-		for i, v := range row {
-			sb.WriteString(fmt.Sprint(v))
-			if i != len(row)-1 {
-				sb.WriteRune(' ')
-			}
-		}
+// TryGet is like Get, but returns an error wrapping ErrOutOfRange instead
+// of panicking if the row or column is outside of the matrix.
+func (m *Dense[T]) TryGet(row, col int) (T, error) {
+	if err := m.accessErr(row, col); err != nil {
+		return 0, err
 	}
+	return m.data[m.index(row, col)], nil
+}
 
-	// TODO: Implement this in terms of Get()
-	sb := strings.Builder{}
-	data := m.data
-	for len(data) > 0 {
-		writeRow(data[:m.cols], &sb)
-		data = data[m.cols:]
-		sb.WriteRune('\n')
+// TrySet is like Set, but returns an error wrapping ErrOutOfRange instead
+// of panicking if the row or column is outside of the matrix.
+func (m *Dense[T]) TrySet(row, col int, value T) error {
+	if err := m.accessErr(row, col); err != nil {
+		return err
 	}
-	return sb.String()[0 : sb.Len()-1]
+	m.data[m.index(row, col)] = value
+	return nil
 }
 
-// Transpose generates a Matrix's transpose if it has not already been generated
-func (m *Matrix) Transpose() *Matrix {
-	if m.transpose != nil {
-		return m.transpose
+func (m *Dense[T]) rowCheck(row int) {
+	if row < 0 || row >= m.rows {
+		err := fmt.Errorf(
+			"%w: row %d is out of range for a matrix with dimensions (%dx%d)",
+			ErrOutOfRange,
+			row,
+			m.rows,
+			m.cols,
+		)
+		panic(err)
 	}
+}
+
+func (m *Dense[T]) colCheck(col int) {
+	if col < 0 || col >= m.cols {
+		err := fmt.Errorf(
+			"%w: col %d is out of range for a matrix with dimensions (%dx%d)",
+			ErrOutOfRange,
+			col,
+			m.rows,
+			m.cols,
+		)
+		panic(err)
+	}
+}
+
+// Row returns a copy of the values in the given row
+func (m *Dense[T]) Row(row int) []T {
+	m.rowCheck(row)
+	result := make([]T, m.cols)
+	for c := range result {
+		result[c] = m.Get(row, c)
+	}
+	return result
+}
 
-	m.transpose = New(m.cols, m.rows)
+// Col returns a copy of the values in the given column
+func (m *Dense[T]) Col(col int) []T {
+	m.colCheck(col)
+	result := make([]T, m.rows)
+	for r := range result {
+		result[r] = m.Get(r, col)
+	}
+	return result
+}
+
+// SetRow overwrites the given row with the supplied values
+func (m *Dense[T]) SetRow(row int, values []T) {
+	m.rowCheck(row)
+	if len(values) != m.cols {
+		err := fmt.Errorf(
+			"matrix: supplied slice is expected to have a length of %d, instead its length is %d",
+			m.cols,
+			len(values),
+		)
+		panic(err)
+	}
+	for c, v := range values {
+		m.Set(row, c, v)
+	}
+}
+
+// SetCol overwrites the given column with the supplied values
+func (m *Dense[T]) SetCol(col int, values []T) {
+	m.colCheck(col)
+	if len(values) != m.rows {
+		err := fmt.Errorf(
+			"matrix: supplied slice is expected to have a length of %d, instead its length is %d",
+			m.rows,
+			len(values),
+		)
+		panic(err)
+	}
+	for r, v := range values {
+		m.Set(r, col, v)
+	}
+}
+
+func (m *Dense[T]) String() string {
+	sb := strings.Builder{}
 	for r := 0; r < m.rows; r++ {
+		if r != 0 {
+			sb.WriteRune('\n')
+		}
 		for c := 0; c < m.cols; c++ {
-			m.transpose.set(c, r, m.Get(r, c))
+			if c != 0 {
+				sb.WriteRune(' ')
+			}
+			sb.WriteString(fmt.Sprint(m.Get(r, c)))
 		}
 	}
-	m.transpose.transpose = m
-	return m.transpose
+	return sb.String()
 }
 
-func dimCheck(rows, cols int) {
+// Transpose returns a view of the Matrix's transpose. The view shares its
+// data with the original matrix, so no copy is made and changes to either
+// matrix are visible through the other.
+func (m *Dense[T]) Transpose() *Dense[T] {
+	return &Dense[T]{
+		rows:      m.cols,
+		cols:      m.rows,
+		rowStride: m.colStride,
+		colStride: m.rowStride,
+		data:      m.data,
+	}
+}
+
+func dimErr(rows, cols int) error {
 	if rows <= 0 || cols <= 0 {
-		panic("matrix: rows and cols cannot be less than or equal to 0")
+		return fmt.Errorf("%w (%dx%d)", ErrInvalidDimensions, rows, cols)
+	}
+	return nil
+}
+
+func dimCheck(rows, cols int) {
+	if err := dimErr(rows, cols); err != nil {
+		panic(err)
 	}
 }
 
-// New returns a matrix with all values set to 0
+// New returns a float64 matrix with all values set to 0
 //
 // Will panic if rows or cols is less than or equal to 0
 func New(rows, cols int) *Matrix {
+	return NewDense[float64](rows, cols)
+}
+
+// NewDense returns a matrix of the given type with all values set to 0
+//
+// Will panic if rows or cols is less than or equal to 0
+func NewDense[T Float](rows, cols int) *Dense[T] {
 	dimCheck(rows, cols)
-	b := &Matrix{rows: rows, cols: cols}
-	b.data = make([]float64, rows*cols)
-	return b
+	return newFromSlice(make([]T, rows*cols), rows, cols)
+}
+
+// TryNew is like New, but returns an error wrapping ErrInvalidDimensions
+// instead of panicking.
+func TryNew(rows, cols int) (*Matrix, error) {
+	return TryNewDense[float64](rows, cols)
+}
+
+// TryNewDense is like NewDense, but returns an error wrapping
+// ErrInvalidDimensions instead of panicking.
+func TryNewDense[T Float](rows, cols int) (*Dense[T], error) {
+	if err := dimErr(rows, cols); err != nil {
+		return nil, err
+	}
+	return newFromSlice(make([]T, rows*cols), rows, cols), nil
 }
 
 // NewFromSlice returns a matrix with all values imported from the
 // supplied slice
-func NewFromSlice(data []float64, rows, cols int) *Matrix {
-	matData := make([]float64, len(data))
+func NewFromSlice[T Float](data []T, rows, cols int) *Dense[T] {
+	m, err := TryNewFromSlice(data, rows, cols)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// TryNewFromSlice is like NewFromSlice, but returns an error instead of
+// panicking if the dimensions are invalid (ErrInvalidDimensions) or the
+// slice doesn't have rows*cols entries (ErrInvalidLength).
+func TryNewFromSlice[T Float](data []T, rows, cols int) (*Dense[T], error) {
+	if err := sliceErr(data, rows, cols); err != nil {
+		return nil, err
+	}
+	matData := make([]T, len(data))
 	copy(matData, data)
-	return newFromSlice(matData, rows, cols)
+	return newFromSlice(matData, rows, cols), nil
 }
 
-func newFromSlice(data []float64, rows, cols int) *Matrix {
-	dimCheck(rows, cols)
+func sliceErr[T Float](data []T, rows, cols int) error {
+	if err := dimErr(rows, cols); err != nil {
+		return err
+	}
 	if len(data) != rows*cols {
-		err := fmt.Errorf(
-			"matrix: supplied slice (%T) is expected to have a length of %d, instead its length is %d",
+		return fmt.Errorf(
+			"%w: supplied slice (%T) is expected to have a length of %d, instead its length is %d",
+			ErrInvalidLength,
 			data,
 			rows*cols,
 			len(data),
 		)
+	}
+	return nil
+}
+
+func newFromSlice[T Float](data []T, rows, cols int) *Dense[T] {
+	if err := sliceErr(data, rows, cols); err != nil {
 		panic(err)
 	}
-	m := &Matrix{rows: rows, cols: cols, data: data}
+	m := &Dense[T]{rows: rows, cols: cols, rowStride: cols, colStride: 1, data: data}
 	return m
 }
 
 // NewRandomNormal returns a matrix with all values sourced from Go's math/rand.NormFloat64
+//
+// Every call draws from the same package-wide generator, which has a fixed
+// seed. It is safe to call concurrently, but then the values each call gets
+// depend on the order in which the calls run. Use NewRandomNormalFrom for
+// reproducible results.
 func NewRandomNormal(rows, cols int) *Matrix {
-	// TODO: Find a way to test this?
+	return NewRandomNormalFrom(random, rows, cols)
+}
+
+// NewRandomNormalFrom returns a matrix with all values sourced from the
+// NormFloat64 method of the supplied generator, filling the matrix in
+// row-major order. The same generator in the same state always produces the
+// same matrix.
+func NewRandomNormalFrom(rng *rand.Rand, rows, cols int) *Matrix {
+	dimCheck(rows, cols)
 	data := make([]float64, rows*cols)
 	for i := range data {
-		data[i] = random.NormFloat64()
+		data[i] = rng.NormFloat64()
 	}
 	return newFromSlice(data, rows, cols)
 }
 
+// NewRandomUniform returns a matrix with all values drawn uniformly from
+// [low, high). Like NewRandomNormal, every call draws from the same
+// package-wide generator; use NewRandomUniformFrom for reproducible results.
+func NewRandomUniform(rows, cols int, low, high float64) *Matrix {
+	return NewRandomUniformFrom(random, rows, cols, low, high)
+}
+
+// NewRandomUniformFrom returns a matrix with all values drawn uniformly from
+// [low, high) with the Float64 method of the supplied generator, filling the
+// matrix in row-major order.
+func NewRandomUniformFrom(rng *rand.Rand, rows, cols int, low, high float64) *Matrix {
+	dimCheck(rows, cols)
+	data := make([]float64, rows*cols)
+	for i := range data {
+		data[i] = low + rng.Float64()*(high-low)
+	}
+	return newFromSlice(data, rows, cols)
+}
+
+// Fill returns a rows x cols matrix with every entry set to value.
+//
+// Will panic if rows or cols is less than or equal to 0
+func Fill[T Float](rows, cols int, value T) *Dense[T] {
+	result := NewDense[T](rows, cols)
+	for i := range result.data {
+		result.data[i] = value
+	}
+	return result
+}
+
+// Identity returns an n x n identity matrix, with ones on its diagonal and
+// zeros everywhere else.
+//
+// Will panic if n is less than or equal to 0
+func Identity[T Float](n int) *Dense[T] {
+	result := NewDense[T](n, n)
+	for i := 0; i < n; i++ {
+		result.Set(i, i, 1)
+	}
+	return result
+}
+
+// FromDiag returns a square matrix with the supplied values on its diagonal
+// and zeros everywhere else. It is the inverse of Diag for a square matrix.
+//
+// Will panic if there are no values
+func FromDiag[T Float](values []T) *Dense[T] {
+	result := NewDense[T](len(values), len(values))
+	for i, value := range values {
+		result.Set(i, i, value)
+	}
+	return result
+}
+
+// Convert returns a copy of a matrix with its entries converted to another
+// type.
+func Convert[To, From Float](m *Dense[From]) *Dense[To] {
+	result := NewDense[To](m.rows, m.cols)
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			result.Set(r, c, To(m.Get(r, c)))
+		}
+	}
+	return result
+}
+
+// overlaps reports whether two matrices may share entries, as is the case
+// for a matrix and its transpose, or two intersecting slices of a matrix.
+func overlaps[T Float](a, b *Dense[T]) bool {
+	// Slices of the same array always share the end of their capacity.
+	end := func(data []T) *T {
+		return &data[:cap(data)][cap(data)-1]
+	}
+	if end(a.data) != end(b.data) {
+		return false
+	}
+	// Measure the span of each matrix's entries from the end of the array.
+	span := func(m *Dense[T]) (first, last int) {
+		return cap(m.data) - m.index(m.rows-1, m.cols-1), cap(m.data)
+	}
+	aFirst, aLast := span(a)
+	bFirst, bLast := span(b)
+	if aFirst > bLast || bFirst > aLast {
+		return false
+	}
+	if a.colStride != 1 || b.colStride != 1 || a.rowStride != b.rowStride || a.rowStride == 0 {
+		return true
+	}
+	// a and b have the same layout as slices of one row-major matrix, so
+	// they share entries only if they share some of its rows and cols. Any
+	// grid of rows of that length works, as long as neither wraps around.
+	stride := a.rowStride
+	offset := cap(a.data)
+	if cap(b.data) > offset {
+		offset = cap(b.data)
+	}
+	corner := func(m *Dense[T]) (row, col int, ok bool) {
+		row, col = (offset-cap(m.data))/stride, (offset-cap(m.data))%stride
+		return row, col, col+m.cols <= stride
+	}
+	aRow, aCol, aOK := corner(a)
+	bRow, bCol, bOK := corner(b)
+	if !aOK || !bOK {
+		return true
+	}
+	return aRow < bRow+b.rows && bRow < aRow+a.rows &&
+		aCol < bCol+b.cols && bCol < aCol+a.cols
+}
+
+// sameView reports whether a and b are the same view of the same data, with
+// the same first entry, dimensions and strides.
+func sameView[T Float](a, b *Dense[T]) bool {
+	return &a.data[0] == &b.data[0] &&
+		a.rows == b.rows && a.cols == b.cols &&
+		a.rowStride == b.rowStride && a.colStride == b.colStride
+}
+
+// unaliased returns op, or a copy of it if it shares entries with dst in any
+// other way than being the same view, since writing to dst would then change
+// entries of op before they are read. Element-wise operations read each
+// entry of an operand before writing the same entry of dst, so dst may
+// safely be exactly the same view as an operand.
+func unaliased[T Float](dst, op *Dense[T]) *Dense[T] {
+	if sameView(dst, op) || !overlaps(dst, op) {
+		return op
+	}
+	return op.Clone()
+}
+
+func dstCheck[T Float](dst *Dense[T], rows, cols int) {
+	if dst.rows != rows || dst.cols != cols {
+		panic(&DimensionError{
+			First:  [2]int{dst.rows, dst.cols},
+			Second: [2]int{rows, cols},
+			Reason: "the destination matrix must have the same dimensions as the result",
+		})
+	}
+}
+
+// Copy copies the entries of src into dst. Both matrices must have the same
+// dimensions. src may share data with dst, for example as its transpose.
+func Copy[T Float](dst, src *Dense[T]) {
+	rows, cols := src.Dimensions()
+	dstCheck(dst, rows, cols)
+	src = unaliased(dst, src)
+	engine[T]().Copy(dst, src)
+}
+
 // Scale scales all of the entries in a matrix by multiplying them with the
 // provided scalar, and returns a new matrix with the result.
-func Scale(mat *Matrix, scalar float64) *Matrix {
+func Scale[T Float](mat *Dense[T], scalar T) *Dense[T] {
+	rows, cols := mat.Dimensions()
+	result := NewDense[T](rows, cols)
+	ScaleTo(result, mat, scalar)
+	return result
+}
+
+// ScaleTo scales all of the entries in mat by the provided scalar and stores
+// the result in dst. dst may be mat itself, or share data with it (see
+// AddTo).
+func ScaleTo[T Float](dst, mat *Dense[T], scalar T) {
 	rows, cols := mat.Dimensions()
-	result := New(rows, cols)
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			result.set(r, c, mat.Get(r, c)*scalar)
+	dstCheck(dst, rows, cols)
+	mat = unaliased(dst, mat)
+	engine[T]().Scale(dst, mat, scalar)
+}
+
+// ScaleInPlace scales all of the entries in mat by the provided scalar.
+func ScaleInPlace[T Float](mat *Dense[T], scalar T) {
+	ScaleTo(mat, mat, scalar)
+}
+
+// AddScaled adds the entries of x multiplied by alpha to dst, which must have
+// the same dimensions as x. x may share data with dst (see AddTo).
+func AddScaled[T Float](dst *Dense[T], alpha T, x *Dense[T]) {
+	rows, cols := x.Dimensions()
+	dstCheck(dst, rows, cols)
+	x = unaliased(dst, x)
+	engine[T]().Axpy(alpha, x, dst)
+}
+
+// broadcastDims returns the dimensions that result from broadcasting two
+// matrices together. Each dimension of the matrices must either be equal, or
+// 1 in one of the matrices, in which case that row or column is repeated to
+// match the other. This means that a row vector, a column vector or a 1x1
+// scalar can be combined with a matrix of any compatible size.
+func broadcastDims[T Float](first, second *Dense[T]) (rows, cols int) {
+	rows, cols, err := tryBroadcastDims(first, second)
+	if err != nil {
+		panic(err)
+	}
+	return rows, cols
+}
+
+func tryBroadcastDims[T Float](first, second *Dense[T]) (rows, cols int, err error) {
+	broadcast := func(a, b int) (int, bool) {
+		switch {
+		case a == b || b == 1:
+			return a, true
+		case a == 1:
+			return b, true
 		}
+		return 0, false
 	}
+	rows, rowsOk := broadcast(first.rows, second.rows)
+	cols, colsOk := broadcast(first.cols, second.cols)
+	if !rowsOk || !colsOk {
+		return 0, 0, newDimensionError(
+			first, second,
+			"the dimensions of the supplied matrices cannot be broadcast together; "+
+				"each dimension must either be equal or 1",
+		)
+	}
+	return rows, cols, nil
+}
+
+// broadcastTo returns a read-only view of m stretched to the given dimensions
+// by repeating its single row or column. The view must never be written to,
+// and must be given back with releaseView once it is no longer used.
+func broadcastTo[T Float](m *Dense[T], rows, cols int) *Dense[T] {
+	if m.rows == rows && m.cols == cols {
+		return m
+	}
+	view := newView(m)
+	if m.rows != rows {
+		view.rows = rows
+		view.rowStride = 0
+	}
+	if m.cols != cols {
+		view.cols = cols
+		view.colStride = 0
+	}
+	return view
+}
+
+// broadcastOperands checks that first and second can be broadcast together
+// into dst and returns views of them with the same dimensions as dst, which
+// must be given back with releaseOperands.
+func broadcastOperands[T Float](dst, first, second *Dense[T]) (*Dense[T], *Dense[T]) {
+	rows, cols := broadcastDims(first, second)
+	dstCheck(dst, rows, cols)
+	return broadcastTo(first, rows, cols), broadcastTo(second, rows, cols)
+}
+
+// releaseOperands gives back the views returned by broadcastOperands.
+func releaseOperands[T Float](a, b, first, second *Dense[T]) {
+	releaseView(a, first)
+	releaseView(b, second)
+}
+
+func sameDimsCheck[T Float](first, second *Dense[T]) {
+	if first.rows != second.rows || first.cols != second.cols {
+		panic(newDimensionError(first, second, "the dimensions of the supplied matrices must be exactly equal"))
+	}
+}
+
+// newBroadcast returns a new matrix with the dimensions that result from
+// broadcasting first and second together.
+func newBroadcast[T Float](first, second *Dense[T]) *Dense[T] {
+	return NewDense[T](broadcastDims(first, second))
+}
+
+// tryBroadcast checks that first and second can be broadcast together before
+// calling op on them.
+func tryBroadcast[T Float](first, second *Dense[T], op func(first, second *Dense[T]) *Dense[T]) (*Dense[T], error) {
+	if _, _, err := tryBroadcastDims(first, second); err != nil {
+		return nil, err
+	}
+	return op(first, second), nil
+}
+
+// Zip runs the given function on every pair of corresponding entries in the
+// two matrices and returns the result. The matrices are broadcast together
+// (see Add).
+func Zip[T Float](first, second *Dense[T], function func(x, y T) T) *Dense[T] {
+	result := newBroadcast(first, second)
+	ZipTo(result, first, second, function)
 	return result
 }
 
+// ZipTo runs the given function on every pair of corresponding entries in the
+// two matrices and stores the result in dst. The matrices are broadcast
+// together (see Add), and dst must have the broadcast dimensions. dst may be
+// one of the operands, or share data with them (see AddTo).
+func ZipTo[T Float](dst, first, second *Dense[T], function func(x, y T) T) {
+	first, second = unaliased(dst, first), unaliased(dst, second)
+	a, b := broadcastOperands(dst, first, second)
+	engine[T]().Zip(dst, a, b, function)
+	releaseOperands(a, b, first, second)
+}
+
 // Add adds two matrices together and returns the result.
-func Add(first *Matrix, second *Matrix) *Matrix {
-	rows, cols := first.Dimensions()
-	r, c := second.Dimensions()
-	if rows != r || cols != c {
-		panic("matrix: the dimensions of the supplied matrices must be exactly equal.")
-	}
-	result := New(rows, cols)
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			result.set(r, c, first.Get(r, c)+second.Get(r, c))
-		}
-	}
+//
+// Like all of the element-wise operations, Add broadcasts its operands in the
+// manner of NumPy: if one matrix has a single row or column (or both, as with
+// a 1x1 scalar), it is repeated to match the dimensions of the other. For
+// example, a rows x 1 bias can be added to every column of a rows x batch
+// matrix.
+func Add[T Float](first *Dense[T], second *Dense[T]) *Dense[T] {
+	result := newBroadcast(first, second)
+	AddTo(result, first, second)
 	return result
 }
 
-// Multiply multiplies two matrices together and returns the result.
-func Multiply(first *Matrix, second *Matrix) *Matrix {
+// TryAdd is like Add, but returns a *DimensionError instead of
+// panicking if the matrices cannot be broadcast together.
+func TryAdd[T Float](first *Dense[T], second *Dense[T]) (*Dense[T], error) {
+	return tryBroadcast(first, second, Add[T])
+}
+
+// AddTo adds two matrices together and stores the result in dst. dst may be
+// one of the operands. An operand that shares data with dst in any other
+// way, such as its transpose or an overlapping slice, is copied first, so
+// the result is always the same as if dst were a separate matrix.
+func AddTo[T Float](dst, first, second *Dense[T]) {
+	first, second = unaliased(dst, first), unaliased(dst, second)
+	a, b := broadcastOperands(dst, first, second)
+	engine[T]().Add(dst, a, b)
+	releaseOperands(a, b, first, second)
+}
+
+// Sub subtracts the second matrix from the first and returns the result.
+func Sub[T Float](first *Dense[T], second *Dense[T]) *Dense[T] {
+	result := newBroadcast(first, second)
+	SubTo(result, first, second)
+	return result
+}
+
+// TrySub is like Sub, but returns a *DimensionError instead of
+// panicking if the matrices cannot be broadcast together.
+func TrySub[T Float](first *Dense[T], second *Dense[T]) (*Dense[T], error) {
+	return tryBroadcast(first, second, Sub[T])
+}
+
+// SubTo subtracts the second matrix from the first and stores the result in
+// dst. dst may be one of the operands, or share data with them (see AddTo).
+func SubTo[T Float](dst, first, second *Dense[T]) {
+	first, second = unaliased(dst, first), unaliased(dst, second)
+	a, b := broadcastOperands(dst, first, second)
+	engine[T]().Sub(dst, a, b)
+	releaseOperands(a, b, first, second)
+}
+
+// Hadamard multiplies the corresponding entries of two matrices together
+// (the element-wise product) and returns the result.
+func Hadamard[T Float](first *Dense[T], second *Dense[T]) *Dense[T] {
+	result := newBroadcast(first, second)
+	HadamardTo(result, first, second)
+	return result
+}
+
+// TryHadamard is like Hadamard, but returns a *DimensionError instead of
+// panicking if the matrices cannot be broadcast together.
+func TryHadamard[T Float](first *Dense[T], second *Dense[T]) (*Dense[T], error) {
+	return tryBroadcast(first, second, Hadamard[T])
+}
+
+// HadamardTo multiplies the corresponding entries of two matrices together
+// and stores the result in dst. dst may be one of the operands, or share
+// data with them (see AddTo).
+func HadamardTo[T Float](dst, first, second *Dense[T]) {
+	first, second = unaliased(dst, first), unaliased(dst, second)
+	a, b := broadcastOperands(dst, first, second)
+	engine[T]().Mul(dst, a, b)
+	releaseOperands(a, b, first, second)
+}
+
+// Div divides the entries of the first matrix by the corresponding entries of
+// the second and returns the result.
+func Div[T Float](first *Dense[T], second *Dense[T]) *Dense[T] {
+	result := newBroadcast(first, second)
+	DivTo(result, first, second)
+	return result
+}
+
+// TryDiv is like Div, but returns a *DimensionError instead of
+// panicking if the matrices cannot be broadcast together.
+func TryDiv[T Float](first *Dense[T], second *Dense[T]) (*Dense[T], error) {
+	return tryBroadcast(first, second, Div[T])
+}
+
+// DivTo divides the entries of the first matrix by the corresponding entries
+// of the second and stores the result in dst. dst may be one of the operands,
+// or share data with them (see AddTo).
+func DivTo[T Float](dst, first, second *Dense[T]) {
+	first, second = unaliased(dst, first), unaliased(dst, second)
+	a, b := broadcastOperands(dst, first, second)
+	engine[T]().Div(dst, a, b)
+	releaseOperands(a, b, first, second)
+}
+
+func mulErr[T Float](first, second *Dense[T]) error {
 	if first.cols != second.rows {
-		err := fmt.Errorf(
-			"matrix: the cols of the first matrix (%dx%d) "+
-				"must be equal to the rows of the second matrix (%dx%d)",
-			first.rows, first.cols, second.rows, second.cols,
+		return newDimensionError(
+			first, second,
+			"the cols of the first matrix must be equal to the rows of the second matrix",
 		)
+	}
+	return nil
+}
+
+func mulCheck[T Float](first, second *Dense[T]) {
+	if err := mulErr(first, second); err != nil {
+		panic(err)
+	}
+}
+
+// Multiply multiplies two matrices together and returns the result.
+func Multiply[T Float](first *Dense[T], second *Dense[T]) *Dense[T] {
+	mulCheck(first, second)
+	result := NewDense[T](first.rows, second.cols)
+	MulInto(result, first, second)
+	return result
+}
+
+// TryMultiply is like Multiply, but returns a *DimensionError instead of
+// panicking if the matrices cannot be multiplied.
+func TryMultiply[T Float](first *Dense[T], second *Dense[T]) (*Dense[T], error) {
+	if err := mulErr(first, second); err != nil {
+		return nil, err
+	}
+	return Multiply(first, second), nil
+}
+
+// MulInto multiplies two matrices together and stores the result in dst. dst
+// must not share any entries with either of the operands, although it may be
+// a slice of the same matrix as long as the two slices don't intersect.
+func MulInto[T Float](dst, first, second *Dense[T]) {
+	mulCheck(first, second)
+	dstCheck(dst, first.rows, second.cols)
+	if overlaps(dst, first) || overlaps(dst, second) {
+		panic("matrix: the destination matrix cannot share data with the operands of a multiplication")
+	}
+	engine[T]().Gemm(1, first, second, 0, dst)
+}
+
+// vectorLength returns the number of entries in a row or column vector, or
+// -1 if m is neither.
+func vectorLength[T Float](m *Dense[T]) int {
+	switch {
+	case m.rows == 1:
+		return m.cols
+	case m.cols == 1:
+		return m.rows
+	}
+	return -1
+}
+
+func outerErr[T Float](first, second *Dense[T]) error {
+	if vectorLength(first) == -1 || vectorLength(second) == -1 {
+		return newDimensionError(first, second, "an outer product needs two row or column vectors")
+	}
+	return nil
+}
+
+// Outer returns the outer product of two vectors, which may each be a row
+// or a column vector: the m x n matrix whose entry (i, j) is the product of
+// entry i of the first and entry j of the second. For column vectors this
+// is first * secondᵀ, such as the gradient of a layer's weights for a single
+// sample, delta * activationᵀ.
+func Outer[T Float](first, second *Dense[T]) *Dense[T] {
+	if err := outerErr(first, second); err != nil {
 		panic(err)
 	}
+	x := first
+	if first.rows != 1 {
+		x = first.Transpose()
+	}
+	y := second
+	if second.rows != 1 {
+		y = second.Transpose()
+	}
+	y = y.Clone()
+	result := NewDense[T](x.cols, y.cols)
+	for i := 0; i < x.cols; i++ {
+		axpy(x.Get(0, i), y.data, result.rawRow(i))
+	}
+	return result
+}
 
-	result := New(first.rows, second.cols)
-	rows, cols := result.Dimensions()
-	trans := second.Transpose()
-	for row := 0; row < rows; row++ {
-		for col := 0; col < cols; col++ {
-			var sum float64 = 0
-			for offset := 0; offset < first.cols; offset++ {
-				sum += first.Get(row, offset) * trans.Get(col, offset)
-			}
-			result.set(row, col, sum)
+// TryOuter is like Outer, but returns a *DimensionError instead of panicking
+// if either matrix is not a vector.
+func TryOuter[T Float](first, second *Dense[T]) (*Dense[T], error) {
+	if err := outerErr(first, second); err != nil {
+		return nil, err
+	}
+	return Outer(first, second), nil
+}
+
+// Kronecker returns the Kronecker product of two matrices: the block matrix
+// made by replacing each entry of the first with that entry multiplied by
+// the whole of the second. For an m x n first matrix and a p x q second one
+// the result is mp x nq.
+func Kronecker[T Float](first, second *Dense[T]) *Dense[T] {
+	p, q := second.rows, second.cols
+	result := NewDense[T](first.rows*p, first.cols*q)
+	for r := 0; r < first.rows; r++ {
+		for c := 0; c < first.cols; c++ {
+			ScaleTo(result.Slice(r*p, (r+1)*p, c*q, (c+1)*q), second, first.Get(r, c))
 		}
 	}
 	return result
 }
 
 // Map runs the given function on every entry in the matrix and returns the result
-func Map(mat *Matrix, function func(float64) float64) *Matrix {
+func Map[T Float](mat *Dense[T], function func(T) T) *Dense[T] {
 	rows, cols := mat.Dimensions()
-	result := New(rows, cols)
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			result.set(r, c, function(mat.Get(r, c)))
-		}
-	}
+	result := NewDense[T](rows, cols)
+	MapTo(result, mat, function)
 	return result
 }
+
+// MapTo runs the given function on every entry in mat and stores the result in
+// dst. dst may be mat itself, or share data with it (see AddTo).
+func MapTo[T Float](dst, mat *Dense[T], function func(T) T) {
+	rows, cols := mat.Dimensions()
+	dstCheck(dst, rows, cols)
+	mat = unaliased(dst, mat)
+	engine[T]().Map(dst, mat, function)
+}
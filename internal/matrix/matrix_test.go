@@ -1,7 +1,12 @@
 package matrix_test
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Anthony-Fiddes/gonne/internal/matrix"
@@ -347,3 +352,419 @@ func TestMap(t *testing.T) {
 		})
 	}
 }
+
+func TestSet(t *testing.T) {
+	m := matrix.New(2, 3)
+	m.Set(1, 2, 5)
+	m.Set(0, 1, -3)
+	expected := "0 -3 0\n0 0 5"
+	if result := m.String(); result != expected {
+		t.Fatalf(
+			"expected the matrix to produce the following string after calling Set:\n\n"+
+				"%s\n\ninstead it produced:\n\n%s",
+			expected,
+			result,
+		)
+	}
+}
+
+func TestRowCol(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	tests := []struct {
+		name     string
+		result   []float64
+		expected []float64
+	}{
+		{"Row 0", m.Row(0), []float64{1, 2, 3}},
+		{"Row 1", m.Row(1), []float64{4, 5, 6}},
+		{"Col 0", m.Col(0), []float64{1, 4}},
+		{"Col 2", m.Col(2), []float64{3, 6}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !reflect.DeepEqual(test.result, test.expected) {
+				t.Fatalf("expected %v but got %v", test.expected, test.result)
+			}
+		})
+	}
+
+	t.Run("Is not mutable", func(t *testing.T) {
+		row := m.Row(0)
+		row[0] = 100
+		if m.Get(0, 0) == row[0] {
+			t.Fatalf("The slice returned by Row is backed by the matrix")
+		}
+	})
+
+	t.Run("SetRow and SetCol", func(t *testing.T) {
+		m := matrix.New(2, 2)
+		m.SetRow(0, []float64{1, 2})
+		m.SetCol(1, []float64{3, 4})
+		expected := "1 3\n0 4"
+		if result := m.String(); result != expected {
+			t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+		}
+	})
+}
+
+func TestInPlace(t *testing.T) {
+	first := matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2)
+	second := matrix.NewFromSlice([]float64{5, 6, 7, 8}, 2, 2)
+	tests := []struct {
+		name     string
+		apply    func(dst *matrix.Matrix)
+		expected *matrix.Matrix
+	}{
+		{
+			"AddTo",
+			func(dst *matrix.Matrix) { matrix.AddTo(dst, first, second) },
+			matrix.Add(first, second),
+		},
+		{
+			"ScaleTo",
+			func(dst *matrix.Matrix) { matrix.ScaleTo(dst, first, 3) },
+			matrix.Scale(first, 3),
+		},
+		{
+			"ScaleInPlace",
+			func(dst *matrix.Matrix) {
+				matrix.Copy(dst, first)
+				matrix.ScaleInPlace(dst, 3)
+			},
+			matrix.Scale(first, 3),
+		},
+		{
+			"MapTo",
+			func(dst *matrix.Matrix) {
+				matrix.MapTo(dst, first, func(x float64) float64 { return x * x })
+			},
+			matrix.Map(first, func(x float64) float64 { return x * x }),
+		},
+		{
+			"MulInto",
+			func(dst *matrix.Matrix) { matrix.MulInto(dst, first, second) },
+			matrix.Multiply(first, second),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dst := matrix.New(2, 2)
+			// Run twice to make sure the destination can be reused.
+			test.apply(dst)
+			test.apply(dst)
+			if !matrix.Equal(dst, test.expected) {
+				t.Fatalf(
+					"expected the destination matrix to contain:\n\n%s\n\n"+
+						"instead it contained:\n\n%s",
+					test.expected,
+					dst,
+				)
+			}
+		})
+	}
+
+	t.Run("AddTo operand as destination", func(t *testing.T) {
+		dst := matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2)
+		matrix.AddTo(dst, dst, second)
+		expected := matrix.Add(first, second)
+		if !matrix.Equal(dst, expected) {
+			t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, dst)
+		}
+	})
+}
+
+func TestTransposeView(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	transpose := m.Transpose()
+
+	t.Run("Reflects changes to the original", func(t *testing.T) {
+		m.Set(0, 2, 30)
+		if result := transpose.Get(2, 0); result != 30 {
+			t.Fatalf("expected the transpose to return 30 at (2, 0), instead it returned %f", result)
+		}
+	})
+
+	t.Run("Reflects changes to the transpose", func(t *testing.T) {
+		transpose.Set(1, 1, 50)
+		if result := m.Get(1, 1); result != 50 {
+			t.Fatalf("expected the matrix to return 50 at (1, 1), instead it returned %f", result)
+		}
+	})
+
+	t.Run("Multiply uses current values", func(t *testing.T) {
+		a := matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2)
+		b := matrix.NewFromSlice([]float64{1, 0, 0, 1}, 2, 2)
+		matrix.Multiply(a, b)
+		b.Set(0, 0, 2)
+		result := matrix.Multiply(a, b)
+		expected := "2 2\n6 4"
+		if result.String() != expected {
+			t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+		}
+	})
+
+	t.Run("Transpose of transpose", func(t *testing.T) {
+		if !matrix.Equal(m.Transpose().Transpose(), m) {
+			t.Fatalf("expected the transpose of the transpose to equal the original matrix")
+		}
+	})
+}
+
+func TestElementWise(t *testing.T) {
+	first := []float64{1, 3, 9, 2, 4, 6, 7, 14, 21}
+	second := []float64{2, 1, 3, 4, 8, 2, 7, 1, 5}
+	tests := []struct {
+		name      string
+		operation func(first, second *matrix.Matrix) *matrix.Matrix
+		function  func(x, y float64) float64
+	}{
+		{"Sub", matrix.Sub[float64], func(x, y float64) float64 { return x - y }},
+		{"Hadamard", matrix.Hadamard[float64], func(x, y float64) float64 { return x * y }},
+		{"Div", matrix.Div[float64], func(x, y float64) float64 { return x / y }},
+		{
+			"Zip",
+			func(first, second *matrix.Matrix) *matrix.Matrix {
+				return matrix.Zip(first, second, func(x, y float64) float64 { return x*x + y })
+			},
+			func(x, y float64) float64 { return x*x + y },
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := matrix.NewFromSlice(first, 3, 3)
+			operand := matrix.NewFromSlice(second, 3, 3)
+			result := test.operation(m, operand)
+			index := 0
+			for row := 0; row < 3; row++ {
+				for col := 0; col < 3; col++ {
+					expectedResult := test.function(first[index], second[index])
+					if result.Get(row, col) != expectedResult {
+						t.Fatalf(
+							"At row %d, col %d the matrix was expected to return %f "+
+								"as prescribed in the test data (%v and %v). "+
+								"Instead it returned %f",
+							row,
+							col,
+							expectedResult,
+							first,
+							second,
+							result.Get(row, col),
+						)
+					}
+					index++
+				}
+			}
+		})
+	}
+
+	t.Run("Mismatched dimensions", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected Sub to panic when given matrices of different dimensions")
+			}
+		}()
+		matrix.Sub(matrix.New(2, 3), matrix.New(3, 2))
+	})
+}
+
+func TestBroadcast(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	tests := []struct {
+		name     string
+		result   *matrix.Matrix
+		expected string
+	}{
+		{
+			"Column vector",
+			matrix.Add(m, matrix.NewFromSlice([]float64{10, 20}, 2, 1)),
+			"11 12 13\n24 25 26",
+		},
+		{
+			"Row vector",
+			matrix.Sub(m, matrix.NewFromSlice([]float64{1, 2, 3}, 1, 3)),
+			"0 0 0\n3 3 3",
+		},
+		{
+			"Scalar",
+			matrix.Hadamard(matrix.NewFromSlice([]float64{2}, 1, 1), m),
+			"2 4 6\n8 10 12",
+		},
+		{
+			"Row and column vector",
+			matrix.Div(
+				matrix.NewFromSlice([]float64{2, 4}, 2, 1),
+				matrix.NewFromSlice([]float64{1, 2}, 1, 2),
+			),
+			"2 1\n4 2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.result.String() != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, test.result)
+			}
+		})
+	}
+
+	t.Run("Incompatible dimensions", func(t *testing.T) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatalf("expected Add to panic when given incompatible matrices")
+			}
+			if !strings.Contains(fmt.Sprint(r), "2x3 and 3x1") {
+				t.Fatalf("expected the panic to describe both dimensions, instead got: %v", r)
+			}
+		}()
+		matrix.Add(m, matrix.New(3, 1))
+	})
+
+	t.Run("Destination must have the broadcast dimensions", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected AddTo to panic when given a destination of the wrong size")
+			}
+		}()
+		bias := matrix.New(2, 1)
+		matrix.AddTo(bias, m, bias)
+	})
+}
+
+func TestFloat32(t *testing.T) {
+	m := matrix.NewFromSlice([]float32{1.5, 2, 3, 4}, 2, 2)
+	identity := matrix.NewFromSlice([]float32{1, 0, 0, 1}, 2, 2)
+	tests := []struct {
+		name     string
+		result   *matrix.Matrix32
+		expected string
+	}{
+		{"Multiply", matrix.Multiply(m, identity), "1.5 2\n3 4"},
+		{"Add", matrix.Add(m, identity), "2.5 2\n3 5"},
+		{"Scale", matrix.Scale(m, 2), "3 4\n6 8"},
+		{"NewDense", matrix.NewDense[float32](1, 2), "0 0"},
+		{"Convert", matrix.Convert[float32](matrix.NewFromSlice([]float64{0.1, 2}, 1, 2)), "0.1 2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.result.String() != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, test.result)
+			}
+		})
+	}
+
+	t.Run("Convert to float64", func(t *testing.T) {
+		converted := matrix.Convert[float64](m)
+		if converted.String() != "1.5 2\n3 4" {
+			t.Fatalf("expected:\n\n1.5 2\n3 4\n\ninstead got:\n\n%s", converted)
+		}
+	})
+}
+
+func TestNewRandomNormalFrom(t *testing.T) {
+	first := matrix.NewRandomNormalFrom(rand.New(rand.NewSource(3)), 4, 5)
+	second := matrix.NewRandomNormalFrom(rand.New(rand.NewSource(3)), 4, 5)
+	if !matrix.Equal(first, second) {
+		t.Fatalf("expected the same seed to give the same matrix, instead got:\n%s\nand\n%s", first, second)
+	}
+	third := matrix.NewRandomNormalFrom(rand.New(rand.NewSource(4)), 4, 5)
+	if matrix.Equal(first, third) {
+		t.Fatalf("expected different seeds to give different matrices, instead both were:\n%s", first)
+	}
+
+	// NewRandomNormal shares one generator, which must be safe to use from
+	// several goroutines at once.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			matrix.NewRandomNormal(10, 10)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *matrix.Matrix
+		expected string
+	}{
+		{"Fill", matrix.Fill(2, 3, 1.5), "1.5 1.5 1.5\n1.5 1.5 1.5"},
+		{"Identity", matrix.Identity[float64](3), "1 0 0\n0 1 0\n0 0 1"},
+		{"FromDiag", matrix.FromDiag([]float64{2, -1}), "2 0\n0 -1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if s := test.result.String(); s != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, s)
+			}
+		})
+	}
+}
+
+func TestNewRandomUniform(t *testing.T) {
+	m := matrix.NewRandomUniformFrom(rand.New(rand.NewSource(1)), 50, 40, -2, 3)
+	if min, max := matrix.Min(m), matrix.Max(m); min < -2 || max >= 3 {
+		t.Fatalf("expected every value to be in [-2, 3), instead they ranged from %f to %f", min, max)
+	}
+	if mean := matrix.Mean(m); mean < 0.3 || mean > 0.7 {
+		t.Fatalf("expected a mean close to 0.5, instead got %f", mean)
+	}
+	again := matrix.NewRandomUniformFrom(rand.New(rand.NewSource(1)), 50, 40, -2, 3)
+	if !matrix.Equal(m, again) {
+		t.Fatalf("expected the same generator to produce the same matrix")
+	}
+	if rows, cols := matrix.NewRandomUniform(2, 5, 0, 1).Dimensions(); rows != 2 || cols != 5 {
+		t.Fatalf("expected a 2x5 matrix, instead got %dx%d", rows, cols)
+	}
+}
+
+func TestOuter(t *testing.T) {
+	column := matrix.Parse("1; 2; 3")
+	row := matrix.Parse("4 5")
+	expected := matrix.Parse("4 5; 8 10; 12 15")
+	tests := []struct {
+		name          string
+		first, second *matrix.Matrix
+		expected      *matrix.Matrix
+	}{
+		{"Column and row", column, row, expected},
+		{"Columns", column, row.Transpose(), expected},
+		{"Rows", column.Transpose(), row, expected},
+		{"Row and column", column.Transpose(), row.Transpose(), expected},
+		{"Views", matrix.Parse("0 1; 0 2; 0 3").ColView(1), matrix.Parse("4 5; 6 7").ColView(0), matrix.Parse("4 6; 8 12; 12 18")},
+		{"Single entries", matrix.Parse("2"), matrix.Parse("3"), matrix.Parse("6")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := matrix.Outer(test.first, test.second); !matrix.Equal(result, test.expected) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, result)
+			}
+		})
+	}
+
+	if _, err := matrix.TryOuter(matrix.New(2, 2), row); !errors.Is(err, matrix.ErrDimensionMismatch) {
+		t.Fatalf("expected an error wrapping %q, instead got %v", matrix.ErrDimensionMismatch, err)
+	}
+}
+
+func TestKronecker(t *testing.T) {
+	tests := []struct {
+		name          string
+		first, second *matrix.Matrix
+		expected      string
+	}{
+		{"2x2 and 2x2", matrix.Parse("1 2; 3 4"), matrix.Parse("0 5; 6 7"), "0 5 0 10\n6 7 12 14\n0 15 0 20\n18 21 24 28"},
+		{"Identity", matrix.Identity[float64](2), matrix.Parse("1 2 3"), "1 2 3 0 0 0\n0 0 0 1 2 3"},
+		{"Scalar", matrix.Parse("2"), matrix.Parse("1; 2"), "2\n4"},
+		{"Transposed", matrix.Parse("1 2").Transpose(), matrix.Parse("1 -1"), "1 -1\n2 -2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := matrix.Kronecker(test.first, test.second).String(); result != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, result)
+			}
+		})
+	}
+}
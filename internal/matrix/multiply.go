@@ -0,0 +1,177 @@
+package matrix
+
+import (
+	"runtime"
+	"sync"
+)
+
+const (
+	// blockSize is the number of rows and cols of the result that are
+	// computed together, so that the rows of both operands they need stay in
+	// cache.
+	blockSize = 64
+	// depthBlockSize is the number of entries of each dot product that are
+	// computed together for the same reason.
+	depthBlockSize = 256
+	// parallelThreshold is the number of multiplications (rows * cols *
+	// depth) above which the work is split across goroutines.
+	parallelThreshold = 64 * 64 * 64
+)
+
+// rowMajor returns the entries of m as consecutive rows of m.cols entries
+// each, where row r starts at r*stride. If m is already laid out that way its
+// data is returned directly, otherwise it is copied into a new slice.
+func rowMajor[T Float](m *Dense[T]) (data []T, stride int) {
+	var scratch []T
+	return rowMajorInto(m, &scratch)
+}
+
+// rowMajorInto is like rowMajor, but copies m into scratch rather than a new
+// slice, growing it if it is too small.
+func rowMajorInto[T Float](m *Dense[T], scratch *[]T) (data []T, stride int) {
+	if m.colStride == 1 {
+		return m.data, m.rowStride
+	}
+	if cap(*scratch) < m.rows*m.cols {
+		*scratch = make([]T, m.rows*m.cols)
+	}
+	data = (*scratch)[:m.rows*m.cols]
+	for r := 0; r < m.rows; r++ {
+		row := data[r*m.cols : (r+1)*m.cols]
+		for c := range row {
+			row[c] = m.data[r*m.rowStride+c*m.colStride]
+		}
+	}
+	return data, m.cols
+}
+
+// gemmJob is a multiplication in progress, which is shared out between
+// workers a block of rows at a time. Jobs are pooled along with the scratch
+// memory that operands are copied into, so that repeated multiplications
+// don't allocate.
+type gemmJob[T Float] struct {
+	alpha, beta        T
+	a, b               []T
+	aStride, bStride   int
+	dst                *Dense[T]
+	rows, cols, depth  int
+	blocks, workers    int
+	aScratch, bScratch []T
+	wg                 sync.WaitGroup
+}
+
+// multiplyBlock computes blockSize rows of the result, starting from r0.
+func (j *gemmJob[T]) multiplyBlock(r0 int) {
+	r1 := r0 + blockSize
+	if r1 > j.rows {
+		r1 = j.rows
+	}
+	for c0 := 0; c0 < j.cols; c0 += blockSize {
+		c1 := c0 + blockSize
+		if c1 > j.cols {
+			c1 = j.cols
+		}
+		for d0 := 0; d0 < j.depth; d0 += depthBlockSize {
+			d1 := d0 + depthBlockSize
+			if d1 > j.depth {
+				d1 = j.depth
+			}
+			for r := r0; r < r1; r++ {
+				aRow := j.a[r*j.aStride+d0 : r*j.aStride+d1]
+				for c := c0; c < c1; c++ {
+					sum := j.alpha * dot(aRow, j.b[c*j.bStride+d0:c*j.bStride+d1])
+					i := j.dst.index(r, c)
+					switch {
+					case d0 != 0:
+						j.dst.data[i] += sum
+					case j.beta == 0:
+						j.dst.data[i] = sum
+					default:
+						j.dst.data[i] = j.beta*j.dst.data[i] + sum
+					}
+				}
+			}
+		}
+	}
+}
+
+// work computes the blocks of rows that belong to the given worker, which
+// are every j.workers-th block starting from the worker's own.
+func (j *gemmJob[T]) work(worker int) {
+	for block := worker; block < j.blocks; block += j.workers {
+		j.multiplyBlock(block * blockSize)
+	}
+}
+
+// runTask does a worker's share of the job for a gemmWorker.
+func (j *gemmJob[T]) runTask(worker int) {
+	j.work(worker)
+	j.wg.Done()
+}
+
+// gemmTask asks a gemmWorker to do its share of a job.
+type gemmTask struct {
+	job    interface{ runTask(worker int) }
+	worker int
+}
+
+var (
+	startGemmWorkers sync.Once
+	gemmTasks        chan gemmTask
+)
+
+// gemmWorker does tasks for large multiplications. The workers are started
+// once and live as long as the program, since starting goroutines for every
+// multiplication would allocate.
+func gemmWorker() {
+	for task := range gemmTasks {
+		task.job.runTask(task.worker)
+	}
+}
+
+// gemm computes dst = alpha*first*second + beta*dst. The operands must
+// already have been checked by MulInto.
+//
+// Every entry of the result is a dot product of a row of first and a column
+// of second, so second is used through its transpose to make both of them
+// contiguous rows. The result is computed in square blocks to keep the rows
+// being used in cache, and the blocks are shared out between goroutines when
+// the multiplication is large enough to make that worthwhile.
+func gemm[T Float](alpha T, first, second *Dense[T], beta T, dst *Dense[T]) {
+	jobs := typedPool[T](&float32Jobs, &float64Jobs)
+	j, _ := jobs.Get().(*gemmJob[T])
+	if j == nil {
+		j = new(gemmJob[T])
+	}
+	j.alpha, j.beta, j.dst = alpha, beta, dst
+	j.rows, j.cols, j.depth = first.rows, second.cols, first.cols
+	j.a, j.aStride = rowMajorInto(first, &j.aScratch)
+	j.b, j.bStride = rowMajorInto(second.Transpose(), &j.bScratch)
+
+	j.blocks = (j.rows + blockSize - 1) / blockSize
+	j.workers = runtime.NumCPU()
+	if j.blocks < j.workers {
+		j.workers = j.blocks
+	}
+	if j.workers <= 1 || j.rows*j.cols*j.depth < parallelThreshold {
+		j.workers = 1
+		j.work(0)
+	} else {
+		startGemmWorkers.Do(func() {
+			gemmTasks = make(chan gemmTask)
+			for w := 1; w < runtime.NumCPU(); w++ {
+				go gemmWorker()
+			}
+		})
+		j.wg.Add(j.workers - 1)
+		for w := 1; w < j.workers; w++ {
+			gemmTasks <- gemmTask{job: j, worker: w}
+		}
+		j.work(0)
+		j.wg.Wait()
+	}
+
+	// Keep the scratch memory, but not the matrices.
+	j.a, j.b, j.dst = nil, nil, nil
+	jobs.Put(j)
+}
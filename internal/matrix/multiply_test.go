@@ -0,0 +1,71 @@
+package matrix_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestMultiplyMatchesNaive(t *testing.T) {
+	tests := []struct {
+		rows, depth, cols int
+	}{
+		{1, 1, 1},
+		{3, 5, 7},
+		{64, 64, 64},
+		{65, 257, 63},
+		{130, 300, 1},
+		{1, 784, 100},
+		{200, 150, 170},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("%dx%d x %dx%d", test.rows, test.depth, test.depth, test.cols)
+		t.Run(name, func(t *testing.T) {
+			first := matrix.NewRandomNormal(test.rows, test.depth)
+			second := matrix.NewRandomNormal(test.depth, test.cols)
+			checkMultiply(t, first, second)
+		})
+	}
+
+	t.Run("Strided operands", func(t *testing.T) {
+		first := matrix.NewRandomNormal(90, 80).Transpose().Slice(3, 75, 10, 80)
+		second := matrix.NewRandomNormal(100, 120).Slice(5, 75, 7, 110)
+		checkMultiply(t, first, second)
+	})
+}
+
+func checkMultiply(t *testing.T, first, second *matrix.Matrix) {
+	t.Helper()
+	rows, _ := first.Dimensions()
+	_, cols := second.Dimensions()
+	expected := matrix.New(rows, cols)
+	matrix.ReferenceEngine[float64]{}.Gemm(1, first, second, 0, expected)
+	result := matrix.Multiply(first, second)
+	if !matrix.EqualApprox(expected, result, 1e-9, 1e-9) {
+		t.Fatalf(
+			"the product was expected to match the one calculated by the reference engine, "+
+				"instead %v",
+			matrix.Diff(expected, result),
+		)
+	}
+}
+
+func BenchmarkMultiply(b *testing.B) {
+	sizes := []int{16, 64, 256}
+	for _, size := range sizes {
+		first := matrix.NewRandomNormal(size, size)
+		second := matrix.NewRandomNormal(size, size)
+		dst := matrix.New(size, size)
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				matrix.MulInto(dst, first, second)
+			}
+		})
+		b.Run(fmt.Sprintf("%dx%d Reference", size, size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				matrix.ReferenceEngine[float64]{}.Gemm(1, first, second, 0, dst)
+			}
+		})
+	}
+}
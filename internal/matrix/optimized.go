@@ -0,0 +1,202 @@
+package matrix
+
+// OptimizedEngine is an Engine that works directly on the data of matrices
+// whose rows are contiguous, which includes every matrix created by New and
+// any view of one that is not a transpose. Anything else is handed to the
+// ReferenceEngine.
+type OptimizedEngine[T Float] struct{}
+
+// contiguous reports whether the entries of each row of every matrix are
+// next to each other in its data.
+func contiguous[T Float](a, b, c *Dense[T]) bool {
+	return a.colStride == 1 && b.colStride == 1 && c.colStride == 1
+}
+
+// rawRow returns the data of the given row of a matrix with contiguous rows.
+func (m *Dense[T]) rawRow(row int) []T {
+	i := row * m.rowStride
+	return m.data[i : i+m.cols]
+}
+
+// Name describes the Engine.
+func (OptimizedEngine[T]) Name() string {
+	return "optimized"
+}
+
+// Gemm computes dst = alpha*a*b + beta*dst.
+func (OptimizedEngine[T]) Gemm(alpha T, a, b *Dense[T], beta T, dst *Dense[T]) {
+	gemm(alpha, a, b, beta, dst)
+}
+
+// Axpy computes y = alpha*x + y.
+func (OptimizedEngine[T]) Axpy(alpha T, x, y *Dense[T]) {
+	if !contiguous(x, y, y) {
+		ReferenceEngine[T]{}.Axpy(alpha, x, y)
+		return
+	}
+	for r := 0; r < y.rows; r++ {
+		axpy(alpha, x.rawRow(r), y.rawRow(r))
+	}
+}
+
+// Scale computes dst = alpha*a.
+func (OptimizedEngine[T]) Scale(dst, a *Dense[T], alpha T) {
+	if !contiguous(dst, a, a) {
+		ReferenceEngine[T]{}.Scale(dst, a, alpha)
+		return
+	}
+	for r := 0; r < dst.rows; r++ {
+		scale(dst.rawRow(r), alpha, a.rawRow(r))
+	}
+}
+
+// Copy copies the entries of src into dst.
+func (OptimizedEngine[T]) Copy(dst, src *Dense[T]) {
+	if !contiguous(dst, src, src) {
+		ReferenceEngine[T]{}.Copy(dst, src)
+		return
+	}
+	for r := 0; r < dst.rows; r++ {
+		copy(dst.rawRow(r), src.rawRow(r))
+	}
+}
+
+// Add computes dst = a + b element-wise.
+func (OptimizedEngine[T]) Add(dst, a, b *Dense[T]) {
+	if !contiguous(dst, a, b) {
+		ReferenceEngine[T]{}.Add(dst, a, b)
+		return
+	}
+	for r := 0; r < dst.rows; r++ {
+		d, x, y := dst.rawRow(r), a.rawRow(r), b.rawRow(r)
+		for i := range d {
+			d[i] = x[i] + y[i]
+		}
+	}
+}
+
+// Sub computes dst = a - b element-wise.
+func (OptimizedEngine[T]) Sub(dst, a, b *Dense[T]) {
+	if !contiguous(dst, a, b) {
+		ReferenceEngine[T]{}.Sub(dst, a, b)
+		return
+	}
+	for r := 0; r < dst.rows; r++ {
+		d, x, y := dst.rawRow(r), a.rawRow(r), b.rawRow(r)
+		for i := range d {
+			d[i] = x[i] - y[i]
+		}
+	}
+}
+
+// Mul computes dst = a * b element-wise.
+func (OptimizedEngine[T]) Mul(dst, a, b *Dense[T]) {
+	if !contiguous(dst, a, b) {
+		ReferenceEngine[T]{}.Mul(dst, a, b)
+		return
+	}
+	for r := 0; r < dst.rows; r++ {
+		d, x, y := dst.rawRow(r), a.rawRow(r), b.rawRow(r)
+		for i := range d {
+			d[i] = x[i] * y[i]
+		}
+	}
+}
+
+// Div computes dst = a / b element-wise.
+func (OptimizedEngine[T]) Div(dst, a, b *Dense[T]) {
+	if !contiguous(dst, a, b) {
+		ReferenceEngine[T]{}.Div(dst, a, b)
+		return
+	}
+	for r := 0; r < dst.rows; r++ {
+		d, x, y := dst.rawRow(r), a.rawRow(r), b.rawRow(r)
+		for i := range d {
+			d[i] = x[i] / y[i]
+		}
+	}
+}
+
+// Map sets every entry of dst to function applied to the entry of a.
+func (OptimizedEngine[T]) Map(dst, a *Dense[T], function func(T) T) {
+	if !contiguous(dst, a, a) {
+		ReferenceEngine[T]{}.Map(dst, a, function)
+		return
+	}
+	for r := 0; r < dst.rows; r++ {
+		d, x := dst.rawRow(r), a.rawRow(r)
+		for i := range d {
+			d[i] = function(x[i])
+		}
+	}
+}
+
+// Zip sets every entry of dst to function applied to the entries of a and b.
+func (OptimizedEngine[T]) Zip(dst, a, b *Dense[T], function func(x, y T) T) {
+	if !contiguous(dst, a, b) {
+		ReferenceEngine[T]{}.Zip(dst, a, b, function)
+		return
+	}
+	for r := 0; r < dst.rows; r++ {
+		d, x, y := dst.rawRow(r), a.rawRow(r), b.rawRow(r)
+		for i := range d {
+			d[i] = function(x[i], y[i])
+		}
+	}
+}
+
+// Sum returns the sum of the entries of a.
+func (OptimizedEngine[T]) Sum(a *Dense[T]) T {
+	if !contiguous(a, a, a) {
+		return ReferenceEngine[T]{}.Sum(a)
+	}
+	var result T
+	for r := 0; r < a.rows; r++ {
+		result += sum(a.rawRow(r))
+	}
+	return result
+}
+
+// Dot returns the sum of the products of the entries of a and b.
+func (OptimizedEngine[T]) Dot(a, b *Dense[T]) T {
+	if !contiguous(a, b, b) {
+		return ReferenceEngine[T]{}.Dot(a, b)
+	}
+	var result T
+	for r := 0; r < a.rows; r++ {
+		result += dot(a.rawRow(r), b.rawRow(r))
+	}
+	return result
+}
+
+// ArgMax returns the position of the first largest entry of a.
+func (OptimizedEngine[T]) ArgMax(a *Dense[T]) (row, col int) {
+	if !contiguous(a, a, a) {
+		return ReferenceEngine[T]{}.ArgMax(a)
+	}
+	best := a.data[0]
+	for r := 0; r < a.rows; r++ {
+		for c, v := range a.rawRow(r) {
+			if v > best {
+				best, row, col = v, r, c
+			}
+		}
+	}
+	return row, col
+}
+
+// ArgMin returns the position of the first smallest entry of a.
+func (OptimizedEngine[T]) ArgMin(a *Dense[T]) (row, col int) {
+	if !contiguous(a, a, a) {
+		return ReferenceEngine[T]{}.ArgMin(a)
+	}
+	best := a.data[0]
+	for r := 0; r < a.rows; r++ {
+		for c, v := range a.rawRow(r) {
+			if v < best {
+				best, row, col = v, r, c
+			}
+		}
+	}
+	return row, col
+}
@@ -0,0 +1,145 @@
+package matrix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse reads a float64 matrix from text. See ParseDense for the formats it
+// accepts. It panics if the text isn't a valid matrix, which makes it
+// convenient for test fixtures; use TryParse to handle the error.
+func Parse(s string) *Matrix {
+	return ParseDense[float64](s)
+}
+
+// TryParse is like Parse, but returns an error instead of panicking.
+func TryParse(s string) (*Matrix, error) {
+	return TryParseDense[float64](s)
+}
+
+// ParseDense reads a matrix from text. It accepts the format of String, with
+// one row per line and entries separated by spaces, as well as MATLAB-style
+// literals such as [1 2; 3 4] and NumPy-style ones such as
+// [[1, 2], [3, 4]]. Entries may be separated by commas or whitespace, and
+// rows by newlines or semicolons. Every entry String prints, including NaN,
+// +Inf and -Inf, is read back exactly.
+//
+// It panics if the text isn't a valid matrix; use TryParseDense to handle
+// the error.
+func ParseDense[T Float](s string) *Dense[T] {
+	m, err := TryParseDense[T](s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// TryParseDense is like ParseDense, but returns an error instead of
+// panicking. The error wraps ErrSyntax if the text is malformed or its rows
+// have different lengths, or ErrInvalidDimensions if it has no entries.
+func TryParseDense[T Float](s string) (*Dense[T], error) {
+	rows, err := splitRows(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the text has no entries", ErrInvalidDimensions)
+	}
+
+	bitSize := 64
+	if isFloat32[T]() {
+		bitSize = 32
+	}
+	cols := len(rows[0])
+	if cols == 0 {
+		return nil, fmt.Errorf("%w: the rows have no entries", ErrInvalidDimensions)
+	}
+	data := make([]T, 0, len(rows)*cols)
+	for r, row := range rows {
+		if len(row) != cols {
+			return nil, fmt.Errorf("%w: row %d has %d entries, but row 0 has %d", ErrSyntax, r, len(row), cols)
+		}
+		for c, field := range row {
+			value, err := strconv.ParseFloat(field, bitSize)
+			if err != nil {
+				return nil, fmt.Errorf("%w: entry (%d, %d) %q is not a number", ErrSyntax, r, c, field)
+			}
+			data = append(data, T(value))
+		}
+	}
+	return newFromSlice(data, len(rows), cols), nil
+}
+
+// splitRows splits the text of a matrix into the fields of each row.
+func splitRows(s string) ([][]string, error) {
+	if !strings.HasPrefix(s, "[") {
+		return splitFlat(s)
+	}
+	if !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("%w: the opening [ is never closed", ErrSyntax)
+	}
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if !strings.HasPrefix(inner, "[") {
+		// A MATLAB-style literal.
+		return splitFlat(inner)
+	}
+
+	// A NumPy-style literal, with each row in its own brackets.
+	var rows [][]string
+	for inner != "" {
+		if !strings.HasPrefix(inner, "[") {
+			return nil, fmt.Errorf("%w: expected [ to start row %d, instead found %q", ErrSyntax, len(rows), inner)
+		}
+		end := strings.IndexByte(inner, ']')
+		if end == -1 {
+			return nil, fmt.Errorf("%w: row %d is never closed", ErrSyntax, len(rows))
+		}
+		row := inner[1:end]
+		if strings.ContainsAny(row, "[;") {
+			return nil, fmt.Errorf("%w: row %d %q can only hold numbers", ErrSyntax, len(rows), row)
+		}
+		entries, err := splitEntries(row)
+		if err != nil {
+			return nil, fmt.Errorf("%w in row %d", err, len(rows))
+		}
+		rows = append(rows, entries)
+		inner = strings.TrimSpace(inner[end+1:])
+		inner = strings.TrimSpace(strings.TrimPrefix(inner, ","))
+	}
+	return rows, nil
+}
+
+// splitFlat splits rows separated by newlines or semicolons, skipping blank
+// ones.
+func splitFlat(s string) ([][]string, error) {
+	if strings.ContainsAny(s, "[]") {
+		return nil, fmt.Errorf("%w: unexpected bracket in %q", ErrSyntax, s)
+	}
+	var rows [][]string
+	for _, line := range strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == ';' }) {
+		row, err := splitEntries(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w in row %d", err, len(rows))
+		}
+		if len(row) != 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// splitEntries splits a row into entries separated by commas or whitespace.
+// Every comma must have an entry on each side of it.
+func splitEntries(row string) ([]string, error) {
+	parts := strings.Split(row, ",")
+	var entries []string
+	for _, part := range parts {
+		fields := strings.Fields(part)
+		if len(fields) == 0 && len(parts) > 1 {
+			return nil, fmt.Errorf("%w: missing entry between commas", ErrSyntax)
+		}
+		entries = append(entries, fields...)
+	}
+	return entries, nil
+}
@@ -0,0 +1,96 @@
+package matrix_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestParse(t *testing.T) {
+	expected := matrix.NewFromSlice([]float64{1, -2.5, 3, 4e-7, 5, 6}, 2, 3)
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"String", "1 -2.5 3\n4e-07 5 6"},
+		{"Padded", "\n  1   -2.5 3\r\n\t4e-07 5  6\n\n"},
+		{"Semicolons", "1 -2.5 3; 4e-07 5 6"},
+		{"MATLAB", "[1 -2.5 3; 4e-7 5 6]"},
+		{"MATLAB commas", "[1, -2.5, 3; 4e-7, 5, 6;]"},
+		{"MATLAB lines", "[\n  1 -2.5 3\n  4e-7 5 6\n]"},
+		{"NumPy", "[[1, -2.5, 3], [4e-7, 5, 6]]"},
+		{"NumPy lines", "[[1., -2.5, 3.],\n [4.e-7, 5., 6.]]"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := matrix.TryParse(test.text)
+			if err != nil {
+				t.Fatalf("expected %q to parse, instead got %v", test.text, err)
+			}
+			if !matrix.Equal(result, expected) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+			}
+		})
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	m := matrix.NewRandomNormalFrom(rand.New(rand.NewSource(1)), 5, 4)
+	m.Set(0, 0, math.Inf(1))
+	m.Set(1, 1, math.Inf(-1))
+	m.Set(2, 2, 1e300)
+	m.Set(3, 3, -0.1)
+	if result := matrix.Parse(m.String()); !matrix.Equal(result, m) {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", m, result)
+	}
+
+	m32 := matrix.Convert[float32](m)
+	if result := matrix.ParseDense[float32](m32.String()); !matrix.Equal(result, m32) {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", m32, result)
+	}
+
+	nan := matrix.Parse("NaN 1")
+	if !math.IsNaN(nan.Get(0, 0)) || nan.Get(0, 1) != 1 {
+		t.Fatalf("expected NaN 1, instead got %s", nan)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want error
+	}{
+		{"Empty", "", matrix.ErrInvalidDimensions},
+		{"Empty brackets", "[]", matrix.ErrInvalidDimensions},
+		{"Empty row", "[[]]", matrix.ErrInvalidDimensions},
+		{"Empty rows", "[[], []]", matrix.ErrInvalidDimensions},
+		{"Empty entry", "[1,,2]", matrix.ErrSyntax},
+		{"Trailing comma", "[[1, 2,], [3, 4]]", matrix.ErrSyntax},
+		{"Ragged", "1 2\n3", matrix.ErrSyntax},
+		{"Ragged NumPy", "[[1, 2], [3]]", matrix.ErrSyntax},
+		{"Not a number", "1 x", matrix.ErrSyntax},
+		{"Unclosed", "[1 2; 3 4", matrix.ErrSyntax},
+		{"Unclosed row", "[[1, 2], [3, 4]", matrix.ErrSyntax},
+		{"Nested too deep", "[[[1]]]", matrix.ErrSyntax},
+		{"Stray bracket", "1 2]", matrix.ErrSyntax},
+		{"Junk between rows", "[[1, 2] x [3, 4]]", matrix.ErrSyntax},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := matrix.TryParse(test.text); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, matrix.ErrSyntax) {
+			t.Fatalf("expected Parse to panic with a syntax error, instead got %v", err)
+		}
+	}()
+	matrix.Parse("1 2\n3")
+}
@@ -0,0 +1,43 @@
+package matrix
+
+import "sync"
+
+// Operations draw the scratch memory they need from these pools rather than
+// allocating it on every call, so that repeating an operation on matrices of
+// the same size, such as a layer of a neural network, doesn't allocate once
+// the pools are warm. Each type of matrix has its own pools.
+var (
+	// float32Views and float64Views hold *Dense headers for broadcast views.
+	float32Views, float64Views sync.Pool
+	// float32Jobs and float64Jobs hold *gemmJobs.
+	float32Jobs, float64Jobs sync.Pool
+)
+
+// typedPool returns whichever of the pools is for matrices of type T.
+func typedPool[T Float](float32Pool, float64Pool *sync.Pool) *sync.Pool {
+	if isFloat32[T]() {
+		return float32Pool
+	}
+	return float64Pool
+}
+
+// newView returns a copy of the header of m, sharing its data, which must be
+// given back with releaseView once it is no longer used.
+func newView[T Float](m *Dense[T]) *Dense[T] {
+	view, _ := typedPool[T](&float32Views, &float64Views).Get().(*Dense[T])
+	if view == nil {
+		view = new(Dense[T])
+	}
+	*view = *m
+	return view
+}
+
+// releaseView gives a view returned by broadcastTo back to the pool, unless
+// it is the original matrix.
+func releaseView[T Float](view, original *Dense[T]) {
+	if view == original {
+		return
+	}
+	*view = Dense[T]{}
+	typedPool[T](&float32Views, &float64Views).Put(view)
+}
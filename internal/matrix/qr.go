@@ -0,0 +1,114 @@
+package matrix
+
+import "fmt"
+
+// QR is the QR decomposition of a matrix A with at least as many rows as
+// cols, calculated with Householder reflections: A = Q * R, where Q has
+// orthonormal columns and R is upper triangular. Q has the same dimensions
+// as A and R is square.
+type QR[T Float] struct {
+	// reflectors holds the unit vector v_k of each Householder reflection
+	// I - 2 * v_k * v_kᵀ in row k, from col k on. Keeping the vectors in
+	// rows makes them contiguous.
+	reflectors    *Dense[T]
+	r             *Dense[T]
+	rankDeficient bool
+}
+
+// NewQR calculates the QR decomposition of a matrix. The error wraps
+// ErrInvalidDimensions if the matrix has more cols than rows.
+func NewQR[T Float](a *Dense[T]) (*QR[T], error) {
+	m, n := a.rows, a.cols
+	if m < n {
+		return nil, fmt.Errorf(
+			"%w: a QR decomposition needs at least as many rows as cols (%dx%d)",
+			ErrInvalidDimensions, m, n,
+		)
+	}
+	// Work on the transpose, so that the columns being reflected are rows.
+	work := a.Transpose().Clone()
+	reflectors := NewDense[T](n, m)
+	r := NewDense[T](n, n)
+	tolerance := singularTolerance(m, vectorNorm(a, Infinity))
+	rankDeficient := false
+	for k := 0; k < n; k++ {
+		x := work.Slice(k, k+1, k, m)
+		length := vectorNorm(x, L2)
+		if length <= tolerance {
+			rankDeficient = true
+		}
+		// A zero column needs no reflection, so its reflector stays 0.
+		if length != 0 {
+			// Reflect x onto -sign(x_0) * |x| * e_0, choosing the sign that
+			// avoids cancellation when calculating v = x - alpha * e_0.
+			alpha := -length
+			if x.Get(0, 0) < 0 {
+				alpha = length
+			}
+			v := reflectors.Slice(k, k+1, k, m)
+			Copy(v, x)
+			v.Set(0, 0, v.Get(0, 0)-alpha)
+			ScaleInPlace(v, 1/vectorNorm(v, L2))
+			r.Set(k, k, alpha)
+			if k+1 < n {
+				reflectRows(work.Slice(k+1, n, 0, m), v, k)
+			}
+		}
+		for j := k + 1; j < n; j++ {
+			r.Set(k, j, work.Get(j, k))
+		}
+	}
+	return &QR[T]{reflectors: reflectors, r: r, rankDeficient: rankDeficient}, nil
+}
+
+// reflectRows applies the reflection I - 2 * v * vᵀ to the part of every row
+// of x from col k on, where v is a row vector.
+func reflectRows[T Float](x, v *Dense[T], k int) {
+	for j := 0; j < x.rows; j++ {
+		row := x.Slice(j, j+1, k, x.cols)
+		AddScaled(row, -2*Dot(v, row), v)
+	}
+}
+
+// Q returns the factor with orthonormal columns.
+func (d *QR[T]) Q() *Dense[T] {
+	n, m := d.reflectors.Dimensions()
+	// Q is the product of the reflections applied to the first n columns of
+	// the identity, in reverse order. Work on its transpose so that they are
+	// rows.
+	qt := NewDense[T](n, m)
+	for i := 0; i < n; i++ {
+		qt.Set(i, i, 1)
+	}
+	for k := n - 1; k >= 0; k-- {
+		reflectRows(qt, d.reflectors.Slice(k, k+1, k, m), k)
+	}
+	return qt.Transpose().Clone()
+}
+
+// R returns the upper triangular factor.
+func (d *QR[T]) R() *Dense[T] {
+	return d.r.Clone()
+}
+
+// Solve returns the least squares solution x that minimizes the Frobenius
+// norm of A * x - b. The error wraps ErrSingular if the columns of A are
+// linearly dependent, or is a *DimensionError if b doesn't have as many rows
+// as A.
+func (d *QR[T]) Solve(b *Dense[T]) (*Dense[T], error) {
+	n, m := d.reflectors.Dimensions()
+	if err := rhsErr(d.reflectors.Transpose(), b); err != nil {
+		return nil, err
+	}
+	if d.rankDeficient {
+		return nil, ErrSingular
+	}
+	// Calculate Qᵀ * b, a column at a time.
+	yt := b.Transpose().Clone()
+	for k := 0; k < n; k++ {
+		reflectRows(yt, d.reflectors.Slice(k, k+1, k, m), k)
+	}
+	x := yt.Slice(0, b.cols, 0, n).Transpose().Clone()
+	solveUpper(d.r, x)
+	return x, nil
+}
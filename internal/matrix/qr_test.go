@@ -0,0 +1,55 @@
+package matrix_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestQR(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name string
+		a    *matrix.Matrix
+	}{
+		{"Square", matrix.NewFromSlice([]float64{12, -51, 4, 6, 167, -68, -4, 24, -41}, 3, 3)},
+		{"Tall", matrix.NewRandomNormalFrom(rng, 9, 4)},
+		{"Column", matrix.NewRandomNormalFrom(rng, 5, 1)},
+		{"Zero column", matrix.NewFromSlice([]float64{1, 0, 2, 0, 3, 0}, 3, 2)},
+		{"Slice", matrix.NewRandomNormalFrom(rng, 8, 8).Slice(1, 7, 2, 5)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			qr, err := matrix.NewQR(test.a)
+			if err != nil {
+				t.Fatalf("expected NewQR to succeed, instead got %v", err)
+			}
+			q, r := qr.Q(), qr.R()
+			product := matrix.Multiply(q, r)
+			if !matrix.EqualApprox(test.a, product, 1e-12, 1e-12) {
+				t.Fatalf("expected Q * R to equal A, instead %v", matrix.Diff(test.a, product))
+			}
+			_, n := q.Dimensions()
+			qtq := matrix.Multiply(q.Transpose(), q)
+			identity := matrix.Identity[float64](n)
+			// A zero column of A has no direction, so Q doesn't need an
+			// orthonormal column for it.
+			if test.name != "Zero column" && !matrix.EqualApprox(qtq, identity, 1e-12, 0) {
+				t.Fatalf("expected Q to have orthonormal columns, instead %v", matrix.Diff(qtq, identity))
+			}
+			for r0 := 0; r0 < n; r0++ {
+				for c := 0; c < r0; c++ {
+					if r.Get(r0, c) != 0 {
+						t.Fatalf("expected R to be upper triangular, instead got:\n%s", r)
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := matrix.NewQR(matrix.New(2, 3)); !errors.Is(err, matrix.ErrInvalidDimensions) {
+		t.Fatalf("expected NewQR to reject a wide matrix, instead got %v", err)
+	}
+}
@@ -0,0 +1,351 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Axis selects the dimension that an operation works along.
+type Axis int
+
+const (
+	// Rows works down the rows of a matrix, so a reduction along Rows
+	// produces one value for each column (a 1 x cols row vector).
+	Rows Axis = iota
+	// Cols works across the columns of a matrix, so a reduction along Cols
+	// produces one value for each row (a rows x 1 column vector).
+	Cols
+)
+
+func (a Axis) String() string {
+	switch a {
+	case Rows:
+		return "Rows"
+	case Cols:
+		return "Cols"
+	}
+	return fmt.Sprintf("Axis(%d)", int(a))
+}
+
+func axisCheck(axis Axis) {
+	if axis != Rows && axis != Cols {
+		panic(fmt.Errorf("matrix: invalid axis %v", axis))
+	}
+}
+
+// lanes returns the vectors that a reduction along axis reduces to single
+// values.
+func lanes[T Float](m *Dense[T], axis Axis) []*Dense[T] {
+	axisCheck(axis)
+	if axis == Rows {
+		result := make([]*Dense[T], m.cols)
+		for c := range result {
+			result[c] = m.ColView(c)
+		}
+		return result
+	}
+	result := make([]*Dense[T], m.rows)
+	for r := range result {
+		result[r] = m.RowView(r)
+	}
+	return result
+}
+
+// reduceAxis applies a whole-matrix reduction to every lane along axis.
+func reduceAxis[T Float](m *Dense[T], axis Axis, reduce func(*Dense[T]) T) *Dense[T] {
+	ls := lanes(m, axis)
+	values := make([]T, len(ls))
+	for i, lane := range ls {
+		values[i] = reduce(lane)
+	}
+	if axis == Rows {
+		return newFromSlice(values, 1, len(values))
+	}
+	return newFromSlice(values, len(values), 1)
+}
+
+// argAxis applies a whole-matrix arg reduction to every lane along axis and
+// returns the index of the chosen entry within each lane.
+func argAxis[T Float](m *Dense[T], axis Axis, arg func(*Dense[T]) (row, col int)) []int {
+	ls := lanes(m, axis)
+	result := make([]int, len(ls))
+	for i, lane := range ls {
+		// Every lane is a vector, so one of row and col is always 0.
+		row, col := arg(lane)
+		result[i] = row + col
+	}
+	return result
+}
+
+// Sum returns the sum of all of the entries in the matrix.
+func Sum[T Float](m *Dense[T]) T {
+	return engine[T]().Sum(m)
+}
+
+// SumAxis returns the sums of the entries along the given axis.
+func SumAxis[T Float](m *Dense[T], axis Axis) *Dense[T] {
+	return reduceAxis(m, axis, Sum[T])
+}
+
+// Diag returns the entries on the main diagonal of the matrix, from its top
+// left corner. A matrix that isn't square has as many as its smaller
+// dimension.
+func Diag[T Float](m *Dense[T]) []T {
+	n := m.rows
+	if m.cols < n {
+		n = m.cols
+	}
+	result := make([]T, n)
+	for i := range result {
+		result[i] = m.Get(i, i)
+	}
+	return result
+}
+
+// Trace returns the sum of the entries on the diagonal of a square matrix.
+// It panics with an error wrapping ErrNotSquare if the matrix is not square.
+func Trace[T Float](m *Dense[T]) T {
+	result, err := TryTrace(m)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// TryTrace is like Trace, but returns an error wrapping ErrNotSquare instead
+// of panicking.
+func TryTrace[T Float](m *Dense[T]) (T, error) {
+	if err := squareErr(m); err != nil {
+		return 0, err
+	}
+	var result T
+	for _, value := range Diag(m) {
+		result += value
+	}
+	return result, nil
+}
+
+// Dot returns the sum of the products of the corresponding entries of two
+// matrices, which must have the same dimensions. For vectors this is the dot
+// product.
+func Dot[T Float](first, second *Dense[T]) T {
+	sameDimsCheck(first, second)
+	return engine[T]().Dot(first, second)
+}
+
+// Mean returns the arithmetic mean of all of the entries in the matrix.
+func Mean[T Float](m *Dense[T]) T {
+	return Sum(m) / T(m.rows*m.cols)
+}
+
+// MeanAxis returns the means of the entries along the given axis.
+func MeanAxis[T Float](m *Dense[T], axis Axis) *Dense[T] {
+	return reduceAxis(m, axis, Mean[T])
+}
+
+// Variance returns the population variance of all of the entries in the
+// matrix.
+func Variance[T Float](m *Dense[T]) T {
+	mean := Mean(m)
+	var sum T
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			d := m.Get(r, c) - mean
+			sum += d * d
+		}
+	}
+	return sum / T(m.rows*m.cols)
+}
+
+// VarianceAxis returns the population variances of the entries along the
+// given axis.
+func VarianceAxis[T Float](m *Dense[T], axis Axis) *Dense[T] {
+	return reduceAxis(m, axis, Variance[T])
+}
+
+// ArgMax returns the position of the largest entry in the matrix. If there
+// is more than one, the first in row-major order is returned.
+func ArgMax[T Float](m *Dense[T]) (row, col int) {
+	return engine[T]().ArgMax(m)
+}
+
+// ArgMaxAxis returns the index of the largest entry along the given axis.
+// For example, ArgMaxAxis(m, Rows) returns the row of the largest entry in
+// each column.
+func ArgMaxAxis[T Float](m *Dense[T], axis Axis) []int {
+	return argAxis(m, axis, ArgMax[T])
+}
+
+// ArgMin returns the position of the smallest entry in the matrix. If there
+// is more than one, the first in row-major order is returned.
+func ArgMin[T Float](m *Dense[T]) (row, col int) {
+	return engine[T]().ArgMin(m)
+}
+
+// ArgMinAxis returns the index of the smallest entry along the given axis.
+func ArgMinAxis[T Float](m *Dense[T], axis Axis) []int {
+	return argAxis(m, axis, ArgMin[T])
+}
+
+// Max returns the largest entry in the matrix.
+func Max[T Float](m *Dense[T]) T {
+	return m.Get(ArgMax(m))
+}
+
+// MaxAxis returns the largest entries along the given axis.
+func MaxAxis[T Float](m *Dense[T], axis Axis) *Dense[T] {
+	return reduceAxis(m, axis, Max[T])
+}
+
+// Min returns the smallest entry in the matrix.
+func Min[T Float](m *Dense[T]) T {
+	return m.Get(ArgMin(m))
+}
+
+// MinAxis returns the smallest entries along the given axis.
+func MinAxis[T Float](m *Dense[T], axis Axis) *Dense[T] {
+	return reduceAxis(m, axis, Min[T])
+}
+
+// NormType selects the norm calculated by Norm.
+type NormType int
+
+const (
+	// L1 is the sum of the absolute values of a vector. For a matrix it is
+	// the largest L1 norm of its columns.
+	L1 NormType = iota
+	// L2 is the Euclidean length of a vector. For a matrix it is the spectral
+	// norm, i.e. its largest singular value.
+	L2
+	// Frobenius is the square root of the sum of the squares of every entry.
+	Frobenius
+	// Infinity is the largest absolute value of a vector. For a matrix it is
+	// the largest L1 norm of its rows.
+	Infinity
+)
+
+func (n NormType) String() string {
+	switch n {
+	case L1:
+		return "L1"
+	case L2:
+		return "L2"
+	case Frobenius:
+		return "Frobenius"
+	case Infinity:
+		return "Infinity"
+	}
+	return fmt.Sprintf("NormType(%d)", int(n))
+}
+
+// Norm returns the given norm of the matrix. Row and column vectors are
+// measured with the vector norms, and any other matrix with the matrix norms
+// induced by them.
+func Norm[T Float](m *Dense[T], norm NormType) T {
+	if m.rows == 1 || m.cols == 1 {
+		return vectorNorm(m, norm)
+	}
+	switch norm {
+	case L1:
+		return Max(reduceAxis(m, Rows, func(col *Dense[T]) T {
+			return vectorNorm(col, L1)
+		}))
+	case L2:
+		return spectralNorm(m)
+	case Frobenius:
+		return vectorNorm(m, Frobenius)
+	case Infinity:
+		return Max(reduceAxis(m, Cols, func(row *Dense[T]) T {
+			return vectorNorm(row, L1)
+		}))
+	}
+	panic(fmt.Errorf("matrix: invalid norm %v", norm))
+}
+
+// NormAxis returns the vector norms of the rows or columns along the given
+// axis.
+func NormAxis[T Float](m *Dense[T], axis Axis, norm NormType) *Dense[T] {
+	return reduceAxis(m, axis, func(lane *Dense[T]) T {
+		return vectorNorm(lane, norm)
+	})
+}
+
+// vectorNorm treats every entry of m as part of a single vector.
+func vectorNorm[T Float](m *Dense[T], norm NormType) T {
+	switch norm {
+	case L2, Frobenius:
+		return T(math.Sqrt(float64(engine[T]().Dot(m, m))))
+	case L1, Infinity:
+	default:
+		panic(fmt.Errorf("matrix: invalid norm %v", norm))
+	}
+
+	var result T
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			v := m.Get(r, c)
+			if v < 0 {
+				v = -v
+			}
+			if norm == L1 {
+				result += v
+			} else if v > result {
+				result = v
+			}
+		}
+	}
+	return result
+}
+
+const spectralNormIterations = 1000
+
+// spectralNorm returns the largest singular value of m.
+func spectralNorm[T Float](m *Dense[T]) T {
+	if d, err := NewSVD(m); err == nil {
+		return d.values[0]
+	}
+	// Power iteration fails only if it starts orthogonal to the dominant
+	// singular vector, which at least one of the standard basis vectors
+	// isn't unless m is zero. Start from an uneven vector first, since it
+	// is unlikely to be orthogonal to it.
+	v := NewDense[T](m.cols, 1)
+	for i := 0; i < m.cols; i++ {
+		v.Set(i, 0, 1/T(i+1))
+	}
+	ScaleInPlace(v, 1/vectorNorm(v, L2))
+	for j := 0; j <= m.cols; j++ {
+		if norm, ok := powerIteration(m, v); ok {
+			return norm
+		}
+		if j < m.cols {
+			v = NewDense[T](m.cols, 1)
+			v.Set(j, 0, 1)
+		}
+	}
+	return 0
+}
+
+// powerIteration finds the largest singular value of m by power iteration
+// on the transpose of m multiplied by m, starting from the unit vector v. It
+// returns false if v is in the null space of m.
+func powerIteration[T Float](m, v *Dense[T]) (T, bool) {
+	mv := NewDense[T](m.rows, 1)
+	w := NewDense[T](m.cols, 1)
+	tolerance := 100 * epsilon[T]()
+	var eigenvalue T
+	for i := 0; i < spectralNormIterations; i++ {
+		MulInto(mv, m, v)
+		MulInto(w, m.Transpose(), mv)
+		length := vectorNorm(w, L2)
+		if length == 0 {
+			return 0, false
+		}
+		ScaleTo(v, w, 1/length)
+		converged := math.Abs(float64(length-eigenvalue)) <= tolerance*float64(length)
+		eigenvalue = length
+		if converged {
+			break
+		}
+	}
+	return T(math.Sqrt(float64(eigenvalue))), true
+}
@@ -0,0 +1,166 @@
+package matrix_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+const tolerance = 1e-9
+
+func TestReductions(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, -2, 3, 4, 5, -6}, 2, 3)
+	tests := []struct {
+		name     string
+		result   float64
+		expected float64
+	}{
+		{"Sum", matrix.Sum(m), 5},
+		{"Mean", matrix.Mean(m), 5.0 / 6},
+		{"Max", matrix.Max(m), 5},
+		{"Min", matrix.Min(m), -6},
+		{"Variance", matrix.Variance(m), 91.0/6 - (5.0/6)*(5.0/6)},
+		{"L1 Norm", matrix.Norm(m, matrix.L1), 9},
+		{"Infinity Norm", matrix.Norm(m, matrix.Infinity), 15},
+		{"Frobenius Norm", matrix.Norm(m, matrix.Frobenius), math.Sqrt(91)},
+		{
+			"L2 Norm",
+			matrix.Norm(matrix.NewFromSlice([]float64{3, 0, 0, -4}, 2, 2), matrix.L2),
+			4,
+		},
+		{
+			"L2 Norm Rank One",
+			matrix.Norm(matrix.NewFromSlice([]float64{1, -1, 1, -1}, 2, 2), matrix.L2),
+			2,
+		},
+		{
+			"L2 Norm Rank One Null Start",
+			matrix.Norm(matrix.NewFromSlice([]float64{1, -2, 1, -2}, 2, 2), matrix.L2),
+			math.Sqrt(10),
+		},
+		{
+			"L2 Norm One Nonzero Row",
+			matrix.Norm(matrix.NewFromSlice([]float64{2, -4, 0, 0, 0, 0, 0, 0, 0}, 3, 3), matrix.L2),
+			math.Sqrt(20),
+		},
+		{
+			"L1 Vector Norm",
+			matrix.Norm(matrix.NewFromSlice([]float64{3, -4}, 1, 2), matrix.L1),
+			7,
+		},
+		{
+			"L2 Vector Norm",
+			matrix.Norm(matrix.NewFromSlice([]float64{3, -4}, 1, 2), matrix.L2),
+			5,
+		},
+		{
+			"Infinity Vector Norm",
+			matrix.Norm(matrix.NewFromSlice([]float64{3, -4}, 2, 1), matrix.Infinity),
+			4,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if math.Abs(test.result-test.expected) > tolerance {
+				t.Fatalf(
+					"expected %s of\n\n%s\n\nto be %f, instead it was %f",
+					test.name,
+					m,
+					test.expected,
+					test.result,
+				)
+			}
+		})
+	}
+}
+
+func TestAxisReductions(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, -2, 3, 4, 5, -6}, 2, 3)
+	tests := []struct {
+		name     string
+		result   *matrix.Matrix
+		expected string
+	}{
+		{"SumAxis Rows", matrix.SumAxis(m, matrix.Rows), "5 3 -3"},
+		{"SumAxis Cols", matrix.SumAxis(m, matrix.Cols), "2\n3"},
+		{"MeanAxis Rows", matrix.MeanAxis(m, matrix.Rows), "2.5 1.5 -1.5"},
+		{"MaxAxis Rows", matrix.MaxAxis(m, matrix.Rows), "4 5 3"},
+		{"MinAxis Cols", matrix.MinAxis(m, matrix.Cols), "-2\n-6"},
+		{"VarianceAxis Rows", matrix.VarianceAxis(m, matrix.Rows), "2.25 12.25 20.25"},
+		{"NormAxis Cols", matrix.NormAxis(m, matrix.Cols, matrix.L1), "6\n15"},
+		{"NormAxis Rows", matrix.NormAxis(m, matrix.Rows, matrix.Infinity), "4 5 6"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.result.String() != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, test.result)
+			}
+		})
+	}
+
+	argTests := []struct {
+		name     string
+		result   []int
+		expected []int
+	}{
+		{"ArgMaxAxis Rows", matrix.ArgMaxAxis(m, matrix.Rows), []int{1, 1, 0}},
+		{"ArgMaxAxis Cols", matrix.ArgMaxAxis(m, matrix.Cols), []int{2, 1}},
+		{"ArgMinAxis Rows", matrix.ArgMinAxis(m, matrix.Rows), []int{0, 0, 1}},
+		{"ArgMinAxis Cols", matrix.ArgMinAxis(m, matrix.Cols), []int{1, 2}},
+	}
+	for _, test := range argTests {
+		t.Run(test.name, func(t *testing.T) {
+			if !reflect.DeepEqual(test.result, test.expected) {
+				t.Fatalf("expected %v but got %v", test.expected, test.result)
+			}
+		})
+	}
+
+	t.Run("ArgMax", func(t *testing.T) {
+		if row, col := matrix.ArgMax(m); row != 1 || col != 1 {
+			t.Fatalf("expected ArgMax to return (1, 1), instead it returned (%d, %d)", row, col)
+		}
+	})
+}
+
+func TestDiagAndTrace(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *matrix.Matrix
+		diag []float64
+	}{
+		{"Square", matrix.Parse("1 2 3; 4 5 6; 7 8 9"), []float64{1, 5, 9}},
+		{"Wide", matrix.Parse("1 2 3; 4 5 6"), []float64{1, 5}},
+		{"Tall", matrix.Parse("1 2 3; 4 5 6").Transpose(), []float64{1, 5}},
+		{"Scalar", matrix.Parse("7"), []float64{7}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diag := matrix.Diag(test.m)
+			if !reflect.DeepEqual(diag, test.diag) {
+				t.Fatalf("expected the diagonal %v, instead got %v", test.diag, diag)
+			}
+			rows, cols := test.m.Dimensions()
+			if rows != cols {
+				return
+			}
+			if fromDiag := matrix.Diag(matrix.FromDiag(diag)); !reflect.DeepEqual(fromDiag, diag) {
+				t.Fatalf("expected FromDiag to put %v on the diagonal, instead got %v", diag, fromDiag)
+			}
+			var expected float64
+			for _, value := range diag {
+				expected += value
+			}
+			if trace := matrix.Trace(test.m); trace != expected {
+				t.Fatalf("expected a trace of %f, instead got %f", expected, trace)
+			}
+		})
+	}
+
+	if _, err := matrix.TryTrace(matrix.New(2, 3)); !errors.Is(err, matrix.ErrNotSquare) {
+		t.Fatalf("expected an error wrapping %q, instead got %v", matrix.ErrNotSquare, err)
+	}
+}
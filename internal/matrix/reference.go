@@ -0,0 +1,128 @@
+package matrix
+
+// ReferenceEngine is an Engine that implements every operation in the most
+// straightforward way possible, entry by entry. It is slow, but it is the
+// standard that other Engines are held to.
+type ReferenceEngine[T Float] struct{}
+
+// Name describes the Engine.
+func (ReferenceEngine[T]) Name() string {
+	return "reference"
+}
+
+// Gemm computes dst = alpha*a*b + beta*dst.
+func (ReferenceEngine[T]) Gemm(alpha T, a, b *Dense[T], beta T, dst *Dense[T]) {
+	rows, cols := dst.Dimensions()
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			var sum T = 0
+			for offset := 0; offset < a.cols; offset++ {
+				sum += a.Get(row, offset) * b.Get(offset, col)
+			}
+			if beta == 0 {
+				dst.Set(row, col, alpha*sum)
+			} else {
+				dst.Set(row, col, alpha*sum+beta*dst.Get(row, col))
+			}
+		}
+	}
+}
+
+// Axpy computes y = alpha*x + y.
+func (e ReferenceEngine[T]) Axpy(alpha T, x, y *Dense[T]) {
+	e.Zip(y, x, y, func(x, y T) T { return alpha*x + y })
+}
+
+// Scale computes dst = alpha*a.
+func (e ReferenceEngine[T]) Scale(dst, a *Dense[T], alpha T) {
+	e.Map(dst, a, func(x T) T { return alpha * x })
+}
+
+// Copy copies the entries of src into dst.
+func (e ReferenceEngine[T]) Copy(dst, src *Dense[T]) {
+	e.Map(dst, src, func(x T) T { return x })
+}
+
+// Add computes dst = a + b element-wise.
+func (e ReferenceEngine[T]) Add(dst, a, b *Dense[T]) {
+	e.Zip(dst, a, b, func(x, y T) T { return x + y })
+}
+
+// Sub computes dst = a - b element-wise.
+func (e ReferenceEngine[T]) Sub(dst, a, b *Dense[T]) {
+	e.Zip(dst, a, b, func(x, y T) T { return x - y })
+}
+
+// Mul computes dst = a * b element-wise.
+func (e ReferenceEngine[T]) Mul(dst, a, b *Dense[T]) {
+	e.Zip(dst, a, b, func(x, y T) T { return x * y })
+}
+
+// Div computes dst = a / b element-wise.
+func (e ReferenceEngine[T]) Div(dst, a, b *Dense[T]) {
+	e.Zip(dst, a, b, func(x, y T) T { return x / y })
+}
+
+// Map sets every entry of dst to function applied to the entry of a.
+func (ReferenceEngine[T]) Map(dst, a *Dense[T], function func(T) T) {
+	for r := 0; r < dst.rows; r++ {
+		for c := 0; c < dst.cols; c++ {
+			dst.Set(r, c, function(a.Get(r, c)))
+		}
+	}
+}
+
+// Zip sets every entry of dst to function applied to the entries of a and b.
+func (ReferenceEngine[T]) Zip(dst, a, b *Dense[T], function func(x, y T) T) {
+	for r := 0; r < dst.rows; r++ {
+		for c := 0; c < dst.cols; c++ {
+			dst.Set(r, c, function(a.Get(r, c), b.Get(r, c)))
+		}
+	}
+}
+
+// Sum returns the sum of the entries of a.
+func (ReferenceEngine[T]) Sum(a *Dense[T]) T {
+	var sum T
+	for r := 0; r < a.rows; r++ {
+		for c := 0; c < a.cols; c++ {
+			sum += a.Get(r, c)
+		}
+	}
+	return sum
+}
+
+// Dot returns the sum of the products of the entries of a and b.
+func (ReferenceEngine[T]) Dot(a, b *Dense[T]) T {
+	var sum T
+	for r := 0; r < a.rows; r++ {
+		for c := 0; c < a.cols; c++ {
+			sum += a.Get(r, c) * b.Get(r, c)
+		}
+	}
+	return sum
+}
+
+// ArgMax returns the position of the first largest entry of a.
+func (ReferenceEngine[T]) ArgMax(a *Dense[T]) (row, col int) {
+	return argBest(a, func(x, best T) bool { return x > best })
+}
+
+// ArgMin returns the position of the first smallest entry of a.
+func (ReferenceEngine[T]) ArgMin(a *Dense[T]) (row, col int) {
+	return argBest(a, func(x, best T) bool { return x < best })
+}
+
+// argBest returns the position of the first entry for which better reports
+// that it beats every entry before it.
+func argBest[T Float](m *Dense[T], better func(x, best T) bool) (row, col int) {
+	best := m.Get(0, 0)
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			if v := m.Get(r, c); better(v, best) {
+				best, row, col = v, r, c
+			}
+		}
+	}
+	return row, col
+}
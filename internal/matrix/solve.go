@@ -0,0 +1,133 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+func squareErr[T Float](m *Dense[T]) error {
+	if m.rows != m.cols {
+		return fmt.Errorf("%w (%dx%d)", ErrNotSquare, m.rows, m.cols)
+	}
+	return nil
+}
+
+// symmetricErr checks that a square matrix is symmetric, with corresponding
+// entries differing by no more than tolerance.
+func symmetricErr[T Float](a *Dense[T], tolerance T) error {
+	for r := 0; r < a.rows; r++ {
+		for c := 0; c < r; c++ {
+			if abs(a.Get(r, c)-a.Get(c, r)) > tolerance {
+				return fmt.Errorf(
+					"%w: the entries at (%d, %d) and (%d, %d) differ",
+					ErrNotSymmetric, r, c, c, r,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// rhsErr checks that b has a row for every row of a.
+func rhsErr[T Float](a, b *Dense[T]) error {
+	if a.rows != b.rows {
+		return newDimensionError(a, b, "the right hand side must have as many rows as the matrix")
+	}
+	return nil
+}
+
+// singularTolerance returns the magnitude below which a pivot of an n x n
+// matrix whose largest entry has the given magnitude is treated as 0.
+func singularTolerance[T Float](n int, largest T) T {
+	return T(float64(n) * epsilon[T]() * float64(largest))
+}
+
+func abs[T Float](x T) T {
+	return T(math.Abs(float64(x)))
+}
+
+// solveLower overwrites x with the solution of l * result = x, where l is
+// lower triangular. If unit is true, the diagonal of l is taken to be all
+// ones and is never read.
+func solveLower[T Float](l, x *Dense[T], unit bool) {
+	for i := 0; i < l.rows; i++ {
+		row := x.RowView(i)
+		for k := 0; k < i; k++ {
+			if v := l.Get(i, k); v != 0 {
+				AddScaled(row, -v, x.RowView(k))
+			}
+		}
+		if !unit {
+			ScaleInPlace(row, 1/l.Get(i, i))
+		}
+	}
+}
+
+// solveUpper overwrites x with the solution of u * result = x, where u is
+// upper triangular.
+func solveUpper[T Float](u, x *Dense[T]) {
+	for i := u.rows - 1; i >= 0; i-- {
+		row := x.RowView(i)
+		for k := i + 1; k < u.rows; k++ {
+			if v := u.Get(i, k); v != 0 {
+				AddScaled(row, -v, x.RowView(k))
+			}
+		}
+		ScaleInPlace(row, 1/u.Get(i, i))
+	}
+}
+
+// Solve returns the matrix x that satisfies a * x = b, where a is square.
+// Each column of b is a separate right hand side. It uses an LU
+// decomposition, so to solve many systems with the same a it is faster to
+// call NewLU once and use LU.Solve.
+//
+// The error wraps ErrNotSquare or ErrSingular if a is not square or is
+// singular, or is a *DimensionError if b doesn't have as many rows as a.
+func Solve[T Float](a, b *Dense[T]) (*Dense[T], error) {
+	lu, err := NewLU(a)
+	if err != nil {
+		return nil, err
+	}
+	return lu.Solve(b)
+}
+
+// LeastSquares returns the matrix x that minimizes the Frobenius norm of
+// a * x - b, where a has at least as many rows as cols and has full column
+// rank. When a is square this is the same as Solve. It uses a QR
+// decomposition.
+//
+// The error wraps ErrInvalidDimensions if a has more cols than rows, or
+// ErrSingular if its columns are linearly dependent, or is a
+// *DimensionError if b doesn't have as many rows as a.
+func LeastSquares[T Float](a, b *Dense[T]) (*Dense[T], error) {
+	qr, err := NewQR(a)
+	if err != nil {
+		return nil, err
+	}
+	return qr.Solve(b)
+}
+
+// Inverse returns the inverse of a square matrix, calculated with an LU
+// decomposition. The error wraps ErrNotSquare or ErrSingular if the matrix
+// is not square or is singular.
+//
+// Solving a system with Solve is faster and more accurate than multiplying
+// by the inverse.
+func Inverse[T Float](a *Dense[T]) (*Dense[T], error) {
+	lu, err := NewLU(a)
+	if err != nil {
+		return nil, err
+	}
+	return lu.Inverse()
+}
+
+// Determinant returns the determinant of a square matrix, calculated with an
+// LU decomposition. The error wraps ErrNotSquare if the matrix is not square.
+func Determinant[T Float](a *Dense[T]) (T, error) {
+	lu, err := NewLU(a)
+	if err != nil {
+		return 0, err
+	}
+	return lu.Determinant(), nil
+}
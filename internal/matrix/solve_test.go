@@ -0,0 +1,123 @@
+package matrix_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestSolve(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a := matrix.NewRandomNormalFrom(rng, 6, 6)
+	b := matrix.NewRandomNormalFrom(rng, 6, 3)
+	x, err := matrix.Solve(a, b)
+	if err != nil {
+		t.Fatalf("expected Solve to succeed, instead got %v", err)
+	}
+	if ax := matrix.Multiply(a, x); !matrix.EqualApprox(ax, b, 1e-10, 1e-10) {
+		t.Fatalf("expected A * x to equal b, instead %v", matrix.Diff(ax, b))
+	}
+
+	inverse, err := matrix.Inverse(a)
+	if err != nil {
+		t.Fatalf("expected Inverse to succeed, instead got %v", err)
+	}
+	if product := matrix.Multiply(a, inverse); !matrix.EqualApprox(product, matrix.Identity[float64](6), 1e-10, 0) {
+		t.Fatalf("expected A times its inverse to be the identity, instead %v", matrix.Diff(product, matrix.Identity[float64](6)))
+	}
+
+	t.Run("float32", func(t *testing.T) {
+		a := matrix.NewFromSlice([]float32{2, 1, 1, 3}, 2, 2)
+		b := matrix.NewFromSlice([]float32{3, 5}, 2, 1)
+		x, err := matrix.Solve(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := matrix.NewFromSlice([]float32{0.8, 1.4}, 2, 1)
+		if !matrix.EqualApprox(x, expected, 1e-6, 1e-6) {
+			t.Fatalf("expected x to be:\n%s\ninstead it was:\n%s", expected, x)
+		}
+	})
+}
+
+func TestSolveErrors(t *testing.T) {
+	singular := matrix.NewFromSlice([]float64{1, 2, 3, 2, 4, 6, 0, 1, 1}, 3, 3)
+	tests := []struct {
+		name string
+		try  func() error
+		want error
+	}{
+		{"Singular", func() error { _, err := matrix.Solve(singular, matrix.New(3, 1)); return err }, matrix.ErrSingular},
+		{"Singular inverse", func() error { _, err := matrix.Inverse(singular); return err }, matrix.ErrSingular},
+		{"Not square", func() error { _, err := matrix.Solve(matrix.New(3, 2), matrix.New(3, 1)); return err }, matrix.ErrNotSquare},
+		{"Determinant not square", func() error { _, err := matrix.Determinant(matrix.New(3, 2)); return err }, matrix.ErrNotSquare},
+		{"Wrong rhs", func() error { _, err := matrix.Solve(matrix.Identity[float64](3), matrix.New(2, 1)); return err }, matrix.ErrDimensionMismatch},
+		{"Dependent columns", func() error { _, err := matrix.LeastSquares(singular, matrix.New(3, 1)); return err }, matrix.ErrSingular},
+		{"Wide", func() error { _, err := matrix.LeastSquares(matrix.New(2, 3), matrix.New(2, 1)); return err }, matrix.ErrInvalidDimensions},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.try(); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        *matrix.Matrix
+		expected float64
+	}{
+		{"1x1", matrix.NewFromSlice([]float64{-3}, 1, 1), -3},
+		{"2x2", matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2), -2},
+		{"3x3", matrix.NewFromSlice([]float64{6, 1, 1, 4, -2, 5, 2, 8, 7}, 3, 3), -306},
+		{"Singular", matrix.NewFromSlice([]float64{1, 2, 2, 4}, 2, 2), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			det, err := matrix.Determinant(test.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(det-test.expected) > 1e-9 {
+				t.Fatalf("expected a determinant of %f, instead got %f", test.expected, det)
+			}
+		})
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	// Fit y = 1 + 2x to points that lie on it exactly, and then to points
+	// scattered evenly around it.
+	a := matrix.NewFromSlice([]float64{1, 0, 1, 1, 1, 2, 1, 3}, 4, 2)
+	tests := []struct {
+		name string
+		b    []float64
+	}{
+		{"Exact", []float64{1, 3, 5, 7}},
+		{"Scattered", []float64{1.5, 2.5, 4.5, 7.5}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := matrix.NewFromSlice(test.b, 4, 1)
+			x, err := matrix.LeastSquares(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The solution of the normal equations Aᵀ * A * x = Aᵀ * b.
+			at := a.Transpose()
+			expected, err := matrix.Solve(matrix.Multiply(at, a), matrix.Multiply(at, b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !matrix.EqualApprox(x, expected, 1e-12, 1e-12) {
+				t.Fatalf("expected the least squares solution:\n%s\ninstead got:\n%s", expected, x)
+			}
+		})
+	}
+}
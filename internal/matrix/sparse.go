@@ -0,0 +1,456 @@
+package matrix
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Sparse is a matrix that only stores its nonzero entries, which saves
+// memory and work when most of them are 0. It is implemented by CSR and CSC.
+type Sparse[T Float] interface {
+	// Dimensions returns the number of rows and columns.
+	Dimensions() (rows, cols int)
+	// NNZ returns the number of stored entries.
+	NNZ() int
+	// Get returns the value at the given row and column.
+	Get(row, col int) T
+	// Do calls f for every stored entry, in the order that they are stored.
+	Do(f func(row, col int, value T))
+	// ToDense returns the matrix as a Dense matrix.
+	ToDense() *Dense[T]
+}
+
+// CSR is a sparse matrix in compressed sparse row format: the stored entries
+// are kept row by row, so it is quick to go through the rows of a CSR
+// matrix. It is the better format for the first operand of a
+// multiplication.
+//
+// A CSR matrix is never modified after it is created, so it is safe to share.
+type CSR[T Float] struct {
+	rows, cols int
+	// The stored entries of row r are at positions indptr[r] up to
+	// indptr[r+1] of indices, which holds their columns in increasing order,
+	// and values.
+	indptr  []int
+	indices []int
+	values  []T
+}
+
+// CSC is a sparse matrix in compressed sparse column format: the stored
+// entries are kept column by column, so it is quick to go through the
+// columns of a CSC matrix. It is the better format for the second operand of
+// a multiplication, such as a batch of sparse samples.
+//
+// A CSC matrix is stored as the CSR form of its transpose, so each is the
+// zero-copy Transpose of the other.
+type CSC[T Float] struct {
+	transpose *CSR[T]
+}
+
+// NewCSR returns a CSR matrix with the given entries, which are given as
+// parallel slices of row indices, column indices and values in any order.
+// Entries at the same position are added together.
+//
+// Will panic if the dimensions are invalid, the slices have different
+// lengths or an entry is out of range; see TryNewCSR.
+func NewCSR[T Float](rows, cols int, rowIndices, colIndices []int, values []T) *CSR[T] {
+	s, err := TryNewCSR(rows, cols, rowIndices, colIndices, values)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// TryNewCSR is like NewCSR, but returns an error wrapping
+// ErrInvalidDimensions, ErrInvalidLength or ErrOutOfRange instead of
+// panicking.
+func TryNewCSR[T Float](rows, cols int, rowIndices, colIndices []int, values []T) (*CSR[T], error) {
+	if err := dimErr(rows, cols); err != nil {
+		return nil, err
+	}
+	if len(rowIndices) != len(values) || len(colIndices) != len(values) {
+		return nil, fmt.Errorf(
+			"%w: there are %d row indices, %d col indices and %d values",
+			ErrInvalidLength, len(rowIndices), len(colIndices), len(values),
+		)
+	}
+	for i, r := range rowIndices {
+		if c := colIndices[i]; r < 0 || r >= rows || c < 0 || c >= cols {
+			return nil, fmt.Errorf(
+				"%w: entry %d at row and col (%d, %d) is outside of a matrix with dimensions (%dx%d)",
+				ErrOutOfRange, i, r, c, rows, cols,
+			)
+		}
+	}
+
+	// Sort the entries by row, then by column, and merge duplicates.
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if rowIndices[a] != rowIndices[b] {
+			return rowIndices[a] < rowIndices[b]
+		}
+		return colIndices[a] < colIndices[b]
+	})
+	s := &CSR[T]{rows: rows, cols: cols, indptr: make([]int, rows+1)}
+	for n, i := range order {
+		r, c := rowIndices[i], colIndices[i]
+		if n > 0 && r == rowIndices[order[n-1]] && c == colIndices[order[n-1]] {
+			s.values[len(s.values)-1] += values[i]
+			continue
+		}
+		s.indices = append(s.indices, c)
+		s.values = append(s.values, values[i])
+		s.indptr[r+1]++
+	}
+	for r := 0; r < rows; r++ {
+		s.indptr[r+1] += s.indptr[r]
+	}
+	return s, nil
+}
+
+// NewCSC returns a CSC matrix with the given entries. See NewCSR.
+func NewCSC[T Float](rows, cols int, rowIndices, colIndices []int, values []T) *CSC[T] {
+	return NewCSR(cols, rows, colIndices, rowIndices, values).Transpose()
+}
+
+// TryNewCSC is like NewCSC, but returns an error instead of panicking. See
+// TryNewCSR.
+func TryNewCSC[T Float](rows, cols int, rowIndices, colIndices []int, values []T) (*CSC[T], error) {
+	s, err := TryNewCSR(cols, rows, colIndices, rowIndices, values)
+	if err != nil {
+		return nil, err
+	}
+	return s.Transpose(), nil
+}
+
+// ToCSR returns the nonzero entries of a matrix as a CSR matrix.
+func ToCSR[T Float](m *Dense[T]) *CSR[T] {
+	s := &CSR[T]{rows: m.rows, cols: m.cols, indptr: make([]int, m.rows+1)}
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			if v := m.Get(r, c); v != 0 {
+				s.indices = append(s.indices, c)
+				s.values = append(s.values, v)
+			}
+		}
+		s.indptr[r+1] = len(s.values)
+	}
+	return s
+}
+
+// ToCSC returns the nonzero entries of a matrix as a CSC matrix.
+func ToCSC[T Float](m *Dense[T]) *CSC[T] {
+	return ToCSR(m.Transpose()).Transpose()
+}
+
+// Dimensions returns the number of rows and columns a matrix has
+func (s *CSR[T]) Dimensions() (rows, cols int) {
+	return s.rows, s.cols
+}
+
+// NNZ returns the number of stored entries.
+func (s *CSR[T]) NNZ() int {
+	return len(s.values)
+}
+
+// Get returns the value at the given row and column
+func (s *CSR[T]) Get(row, col int) T {
+	if row < 0 || col < 0 || row >= s.rows || col >= s.cols {
+		panic(fmt.Errorf(
+			"%w: row and col (%d, %d) are outside of a matrix with dimensions (%dx%d)",
+			ErrOutOfRange, row, col, s.rows, s.cols,
+		))
+	}
+	start, end := s.indptr[row], s.indptr[row+1]
+	i := start + sort.SearchInts(s.indices[start:end], col)
+	if i < end && s.indices[i] == col {
+		return s.values[i]
+	}
+	return 0
+}
+
+// Do calls f for every stored entry, row by row.
+func (s *CSR[T]) Do(f func(row, col int, value T)) {
+	for r := 0; r < s.rows; r++ {
+		for i := s.indptr[r]; i < s.indptr[r+1]; i++ {
+			f(r, s.indices[i], s.values[i])
+		}
+	}
+}
+
+// ToDense returns the matrix as a Dense matrix.
+func (s *CSR[T]) ToDense() *Dense[T] {
+	result := NewDense[T](s.rows, s.cols)
+	s.Do(result.Set)
+	return result
+}
+
+func (s *CSR[T]) String() string {
+	return s.ToDense().String()
+}
+
+// Transpose returns the transpose of the matrix, which shares its data.
+func (s *CSR[T]) Transpose() *CSC[T] {
+	return &CSC[T]{transpose: s}
+}
+
+// ToCSC returns the matrix in CSC format.
+func (s *CSR[T]) ToCSC() *CSC[T] {
+	return transposeCSR(s).Transpose()
+}
+
+// transposeCSR returns the CSR form of the transpose of s.
+func transposeCSR[T Float](s *CSR[T]) *CSR[T] {
+	t := &CSR[T]{
+		rows:    s.cols,
+		cols:    s.rows,
+		indptr:  make([]int, s.cols+1),
+		indices: make([]int, len(s.indices)),
+		values:  make([]T, len(s.values)),
+	}
+	for _, c := range s.indices {
+		t.indptr[c+1]++
+	}
+	for c := 0; c < s.cols; c++ {
+		t.indptr[c+1] += t.indptr[c]
+	}
+	// Going through s row by row keeps the columns of t sorted.
+	next := make([]int, s.cols)
+	copy(next, t.indptr)
+	s.Do(func(row, col int, value T) {
+		t.indices[next[col]] = row
+		t.values[next[col]] = value
+		next[col]++
+	})
+	return t
+}
+
+// Scale returns the matrix multiplied by a scalar.
+func (s *CSR[T]) Scale(scalar T) *CSR[T] {
+	return s.Map(func(v T) T {
+		return v * scalar
+	})
+}
+
+// Map runs the given function on every stored entry and returns the result.
+// The entries that aren't stored stay 0, so the function should map 0 to 0.
+func (s *CSR[T]) Map(function func(T) T) *CSR[T] {
+	result := &CSR[T]{
+		rows:    s.rows,
+		cols:    s.cols,
+		indptr:  s.indptr,
+		indices: s.indices,
+		values:  make([]T, len(s.values)),
+	}
+	for i, v := range s.values {
+		result.values[i] = function(v)
+	}
+	return result
+}
+
+// Dimensions returns the number of rows and columns a matrix has
+func (s *CSC[T]) Dimensions() (rows, cols int) {
+	return s.transpose.cols, s.transpose.rows
+}
+
+// NNZ returns the number of stored entries.
+func (s *CSC[T]) NNZ() int {
+	return s.transpose.NNZ()
+}
+
+// Get returns the value at the given row and column
+func (s *CSC[T]) Get(row, col int) T {
+	return s.transpose.Get(col, row)
+}
+
+// Do calls f for every stored entry, column by column.
+func (s *CSC[T]) Do(f func(row, col int, value T)) {
+	s.transpose.Do(func(row, col int, value T) {
+		f(col, row, value)
+	})
+}
+
+// ToDense returns the matrix as a Dense matrix.
+func (s *CSC[T]) ToDense() *Dense[T] {
+	rows, cols := s.Dimensions()
+	result := NewDense[T](rows, cols)
+	s.Do(result.Set)
+	return result
+}
+
+func (s *CSC[T]) String() string {
+	return s.ToDense().String()
+}
+
+// Transpose returns the transpose of the matrix, which shares its data.
+func (s *CSC[T]) Transpose() *CSR[T] {
+	return s.transpose
+}
+
+// ToCSR returns the matrix in CSR format.
+func (s *CSC[T]) ToCSR() *CSR[T] {
+	return transposeCSR(s.transpose)
+}
+
+// Scale returns the matrix multiplied by a scalar.
+func (s *CSC[T]) Scale(scalar T) *CSC[T] {
+	return s.transpose.Scale(scalar).Transpose()
+}
+
+// Map runs the given function on every stored entry and returns the result.
+// The entries that aren't stored stay 0, so the function should map 0 to 0.
+func (s *CSC[T]) Map(function func(T) T) *CSC[T] {
+	return s.transpose.Map(function).Transpose()
+}
+
+// zipCSR combines the entries of two CSR matrices with the same dimensions.
+// If union is true, every position stored in either matrix is combined, with
+// 0 for the missing entry; otherwise only the positions stored in both are.
+// Results that are exactly 0 are not stored.
+func zipCSR[T Float](first, second *CSR[T], union bool, function func(x, y T) T) *CSR[T] {
+	if first.rows != second.rows || first.cols != second.cols {
+		panic(&DimensionError{
+			First:  [2]int{first.rows, first.cols},
+			Second: [2]int{second.rows, second.cols},
+			Reason: "the dimensions of the supplied matrices must be exactly equal",
+		})
+	}
+	result := &CSR[T]{rows: first.rows, cols: first.cols, indptr: make([]int, first.rows+1)}
+	store := func(col int, v T) {
+		if v != 0 {
+			result.indices = append(result.indices, col)
+			result.values = append(result.values, v)
+		}
+	}
+	for r := 0; r < first.rows; r++ {
+		i, iEnd := first.indptr[r], first.indptr[r+1]
+		j, jEnd := second.indptr[r], second.indptr[r+1]
+		for i < iEnd || j < jEnd {
+			switch {
+			case j == jEnd || i < iEnd && first.indices[i] < second.indices[j]:
+				if union {
+					store(first.indices[i], function(first.values[i], 0))
+				}
+				i++
+			case i == iEnd || second.indices[j] < first.indices[i]:
+				if union {
+					store(second.indices[j], function(0, second.values[j]))
+				}
+				j++
+			default:
+				store(first.indices[i], function(first.values[i], second.values[j]))
+				i++
+				j++
+			}
+		}
+		result.indptr[r+1] = len(result.values)
+	}
+	return result
+}
+
+// AddSparse adds two CSR matrices with the same dimensions together and
+// returns the result. For CSC matrices, add their transposes.
+func AddSparse[T Float](first, second *CSR[T]) *CSR[T] {
+	return zipCSR(first, second, true, func(x, y T) T {
+		return x + y
+	})
+}
+
+// SubSparse subtracts the second CSR matrix from the first and returns the
+// result.
+func SubSparse[T Float](first, second *CSR[T]) *CSR[T] {
+	return zipCSR(first, second, true, func(x, y T) T {
+		return x - y
+	})
+}
+
+// HadamardSparse multiplies the corresponding entries of two CSR matrices
+// together and returns the result, which only has entries where both of
+// them do.
+func HadamardSparse[T Float](first, second *CSR[T]) *CSR[T] {
+	return zipCSR(first, second, false, func(x, y T) T {
+		return x * y
+	})
+}
+
+func sparseMulErr(firstRows, firstCols, secondRows, secondCols int) error {
+	if firstCols != secondRows {
+		return &DimensionError{
+			First:  [2]int{firstRows, firstCols},
+			Second: [2]int{secondRows, secondCols},
+			Reason: "the cols of the first matrix must be equal to the rows of the second matrix",
+		}
+	}
+	return nil
+}
+
+// MultiplySparseDense multiplies a sparse matrix by a dense one and returns
+// the result. It takes time in proportion to the number of stored entries
+// of the sparse matrix rather than its size.
+func MultiplySparseDense[T Float](first Sparse[T], second *Dense[T]) *Dense[T] {
+	rows, depth := first.Dimensions()
+	if err := sparseMulErr(rows, depth, second.rows, second.cols); err != nil {
+		panic(err)
+	}
+	// Each stored entry (i, k) adds a multiple of row k of second to row i
+	// of the result.
+	result := NewDense[T](rows, second.cols)
+	data, stride := rowMajor(second)
+	first.Do(func(i, k int, value T) {
+		axpy(value, data[k*stride:k*stride+second.cols], result.rawRow(i))
+	})
+	return result
+}
+
+// MultiplyDenseSparse multiplies a dense matrix by a sparse one and returns
+// the result. It takes time in proportion to the number of stored entries
+// of the sparse matrix multiplied by the rows of the dense one, rather than
+// the size of the sparse matrix.
+func MultiplyDenseSparse[T Float](first *Dense[T], second Sparse[T]) *Dense[T] {
+	depth, cols := second.Dimensions()
+	if err := sparseMulErr(first.rows, first.cols, depth, cols); err != nil {
+		panic(err)
+	}
+	result := NewDense[T](first.rows, cols)
+	a, stride := rowMajor(first)
+	switch s := second.(type) {
+	case *CSC[T]:
+		// Entry (i, j) of the result is the dot product of row i of first
+		// and the stored entries of column j of second.
+		t := s.transpose
+		for i := 0; i < first.rows; i++ {
+			aRow, resultRow := a[i*stride:i*stride+first.cols], result.rawRow(i)
+			for j := range resultRow {
+				indices := t.indices[t.indptr[j]:t.indptr[j+1]]
+				values := t.values[t.indptr[j]:t.indptr[j+1]]
+				var sum T
+				for p, k := range indices {
+					sum += aRow[k] * values[p]
+				}
+				resultRow[j] = sum
+			}
+		}
+	case *CSR[T]:
+		// Each stored entry (k, j) adds a multiple of entry (i, k) of first
+		// to entry (i, j) of the result, for every row i.
+		for i := 0; i < first.rows; i++ {
+			aRow, resultRow := a[i*stride:i*stride+first.cols], result.rawRow(i)
+			for k, x := range aRow {
+				for p := s.indptr[k]; p < s.indptr[k+1]; p++ {
+					resultRow[s.indices[p]] += x * s.values[p]
+				}
+			}
+		}
+	default:
+		second.Do(func(k, j int, value T) {
+			for i := 0; i < first.rows; i++ {
+				result.data[i*cols+j] += a[i*stride+k] * value
+			}
+		})
+	}
+	return result
+}
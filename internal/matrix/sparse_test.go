@@ -0,0 +1,212 @@
+package matrix_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+// randomSparse returns a random matrix where about the given fraction of
+// the entries are 0.
+func randomSparse(rng *rand.Rand, rows, cols int, zeros float64) *matrix.Matrix {
+	m := matrix.NewRandomNormalFrom(rng, rows, cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if rng.Float64() < zeros {
+				m.Set(r, c, 0)
+			}
+		}
+	}
+	return m
+}
+
+func TestNewCSR(t *testing.T) {
+	// The entries are out of order, and two of them are at (1, 2).
+	s := matrix.NewCSR(3, 4, []int{1, 0, 2, 1, 1}, []int{2, 3, 0, 0, 2}, []float64{5, 1, 7, 4, 0.5})
+	expected := "0 0 0 1\n4 0 5.5 0\n7 0 0 0"
+	if s.String() != expected {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, s)
+	}
+	if nnz := s.NNZ(); nnz != 4 {
+		t.Fatalf("expected 4 stored entries, instead there were %d", nnz)
+	}
+	if v := s.Get(1, 2); v != 5.5 {
+		t.Fatalf("expected 5.5 at (1, 2), instead got %f", v)
+	}
+	if v := s.Get(2, 3); v != 0 {
+		t.Fatalf("expected 0 at (2, 3), instead got %f", v)
+	}
+
+	c := matrix.NewCSC(3, 4, []int{1, 0, 2, 1, 1}, []int{2, 3, 0, 0, 2}, []float64{5, 1, 7, 4, 0.5})
+	if c.String() != expected {
+		t.Fatalf("expected the CSC matrix:\n\n%s\n\ninstead got:\n\n%s", expected, c)
+	}
+	if v := c.Get(1, 2); v != 5.5 {
+		t.Fatalf("expected 5.5 at (1, 2) of the CSC matrix, instead got %f", v)
+	}
+}
+
+func TestNewCSRErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		rows, cols     int
+		rowIdx, colIdx []int
+		values         []float64
+		want           error
+	}{
+		{"Invalid dimensions", 0, 2, nil, nil, nil, matrix.ErrInvalidDimensions},
+		{"Lengths differ", 2, 2, []int{0, 1}, []int{0}, []float64{1, 2}, matrix.ErrInvalidLength},
+		{"Row out of range", 2, 2, []int{2}, []int{0}, []float64{1}, matrix.ErrOutOfRange},
+		{"Negative col", 2, 2, []int{0}, []int{-1}, []float64{1}, matrix.ErrOutOfRange},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := matrix.TryNewCSR(test.rows, test.cols, test.rowIdx, test.colIdx, test.values); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+			if _, err := matrix.TryNewCSC(test.rows, test.cols, test.rowIdx, test.colIdx, test.values); !errors.Is(err, test.want) {
+				t.Fatalf("expected TryNewCSC to return an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestSparseConversions(t *testing.T) {
+	dense := randomSparse(rand.New(rand.NewSource(1)), 7, 5, 0.7)
+	csr, csc := matrix.ToCSR(dense), matrix.ToCSC(dense)
+	tests := []struct {
+		name   string
+		result *matrix.Matrix
+	}{
+		{"CSR", csr.ToDense()},
+		{"CSC", csc.ToDense()},
+		{"CSR to CSC", csr.ToCSC().ToDense()},
+		{"CSC to CSR", csc.ToCSR().ToDense()},
+		{"CSR transpose", csr.Transpose().Transpose().ToDense()},
+		{"CSC transpose", csc.Transpose().ToDense().Transpose()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !matrix.Equal(test.result, dense) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", dense, test.result)
+			}
+		})
+	}
+
+	var stored int
+	csc.Do(func(row, col int, value float64) {
+		if dense.Get(row, col) != value {
+			t.Fatalf("expected Do to visit %f at (%d, %d), instead it visited %f", dense.Get(row, col), row, col, value)
+		}
+		stored++
+	})
+	if stored != csr.NNZ() || stored != csc.NNZ() {
+		t.Fatalf("expected Do to visit all %d stored entries, instead it visited %d", csr.NNZ(), stored)
+	}
+}
+
+// otherSparse hides the type of a sparse matrix, to test how operations
+// handle implementations of Sparse other than CSR and CSC.
+type otherSparse struct {
+	matrix.Sparse[float64]
+}
+
+func TestSparseMultiply(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	sizes := []struct {
+		rows, depth, cols int
+	}{
+		{1, 1, 1},
+		{5, 8, 3},
+		{30, 40, 20},
+	}
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%dx%d", size.rows, size.depth, size.cols), func(t *testing.T) {
+			sparse := randomSparse(rng, size.rows, size.depth, 0.8)
+			dense := matrix.NewRandomNormalFrom(rng, size.depth, size.cols)
+			expected := matrix.Multiply(sparse, dense)
+			for name, s := range map[string]matrix.Sparse[float64]{"CSR": matrix.ToCSR(sparse), "CSC": matrix.ToCSC(sparse)} {
+				for _, second := range []*matrix.Matrix{dense, dense.Transpose().Clone().Transpose()} {
+					if result := matrix.MultiplySparseDense(s, second); !matrix.EqualApprox(result, expected, 1e-12, 1e-12) {
+						t.Fatalf("expected the %s product to match the dense one, instead %v", name, matrix.Diff(result, expected))
+					}
+				}
+			}
+
+			sparseT := sparse.Transpose()
+			expected = matrix.Multiply(dense.Transpose(), sparseT)
+			for name, s := range map[string]matrix.Sparse[float64]{
+				"CSR":   matrix.ToCSR(sparseT),
+				"CSC":   matrix.ToCSC(sparseT),
+				"Other": otherSparse{matrix.ToCSR(sparseT)},
+			} {
+				if result := matrix.MultiplyDenseSparse(dense.Transpose(), s); !matrix.EqualApprox(result, expected, 1e-12, 1e-12) {
+					t.Fatalf("expected the %s product to match the dense one, instead %v", name, matrix.Diff(result, expected))
+				}
+			}
+		})
+	}
+
+	t.Run("Wrong dimensions", func(t *testing.T) {
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, matrix.ErrDimensionMismatch) {
+				t.Fatalf("expected a panic with a dimension mismatch, instead got %v", err)
+			}
+		}()
+		matrix.MultiplySparseDense[float64](matrix.ToCSR(matrix.New(2, 3)), matrix.New(2, 3))
+	})
+}
+
+func TestSparseElementWise(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	a, b := randomSparse(rng, 6, 7, 0.6), randomSparse(rng, 6, 7, 0.6)
+	sa, sb := matrix.ToCSR(a), matrix.ToCSR(b)
+	tests := []struct {
+		name     string
+		result   *matrix.CSR[float64]
+		expected *matrix.Matrix
+	}{
+		{"AddSparse", matrix.AddSparse(sa, sb), matrix.Add(a, b)},
+		{"SubSparse", matrix.SubSparse(sa, sb), matrix.Sub(a, b)},
+		{"HadamardSparse", matrix.HadamardSparse(sa, sb), matrix.Hadamard(a, b)},
+		{"Cancelling", matrix.SubSparse(sa, sa), matrix.New(6, 7)},
+		{"Scale", sa.Scale(3), matrix.Scale(a, 3)},
+		{"Map", sa.Map(func(x float64) float64 { return x * x }), matrix.Hadamard(a, a)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := test.result.ToDense(); !matrix.Equal(result, test.expected) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, result)
+			}
+		})
+	}
+
+	if nnz := matrix.SubSparse(sa, sa).NNZ(); nnz != 0 {
+		t.Fatalf("expected entries that cancel out not to be stored, instead there were %d", nnz)
+	}
+	if result := sa.ToCSC().Scale(2).ToDense(); !matrix.Equal(result, matrix.Scale(a, 2)) {
+		t.Fatalf("expected a scaled CSC matrix to be:\n\n%s\n\ninstead got:\n\n%s", matrix.Scale(a, 2), result)
+	}
+}
+
+func BenchmarkMultiplyDenseSparse(b *testing.B) {
+	// A layer with 784 inputs and 100 outputs applied to a batch of 64
+	// inputs that are 80% zeros, like MNIST images.
+	rng := rand.New(rand.NewSource(1))
+	weights := matrix.NewRandomNormalFrom(rng, 100, 784)
+	input := randomSparse(rng, 784, 64, 0.8)
+	b.Run("Dense", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			matrix.Multiply(weights, input)
+		}
+	})
+	b.Run("CSC", func(b *testing.B) {
+		sparse := matrix.ToCSC(input)
+		for i := 0; i < b.N; i++ {
+			matrix.MultiplyDenseSparse[float64](weights, sparse)
+		}
+	})
+}
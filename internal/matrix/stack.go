@@ -0,0 +1,96 @@
+package matrix
+
+import "fmt"
+
+// HStack joins matrices side by side, so that the columns of each matrix
+// follow the columns of the one before it. Every matrix must have the same
+// number of rows.
+func HStack[T Float](mats ...*Dense[T]) *Dense[T] {
+	return Concat(Cols, mats...)
+}
+
+// VStack joins matrices on top of each other, so that the rows of each
+// matrix follow the rows of the one before it. Every matrix must have the
+// same number of cols.
+func VStack[T Float](mats ...*Dense[T]) *Dense[T] {
+	return Concat(Rows, mats...)
+}
+
+// Concat joins matrices along the given axis and returns the result in a new
+// matrix. Concat(Rows, ...) is equivalent to VStack and Concat(Cols, ...) to
+// HStack.
+func Concat[T Float](axis Axis, mats ...*Dense[T]) *Dense[T] {
+	axisCheck(axis)
+	if len(mats) == 0 {
+		panic("matrix: at least one matrix must be supplied to be joined")
+	}
+
+	rows, cols := mats[0].Dimensions()
+	for i, m := range mats[1:] {
+		r, c := m.Dimensions()
+		if axis == Rows && c != cols || axis == Cols && r != rows {
+			err := fmt.Errorf(
+				"%w: cannot join a matrix (%dx%d) along %v with matrix %d (%dx%d)",
+				ErrDimensionMismatch, rows, cols, axis, i+1, r, c,
+			)
+			panic(err)
+		}
+		if axis == Rows {
+			rows += r
+		} else {
+			cols += c
+		}
+	}
+
+	result := NewDense[T](rows, cols)
+	offset := 0
+	for _, m := range mats {
+		r, c := m.Dimensions()
+		if axis == Rows {
+			Copy(result.Slice(offset, offset+r, 0, cols), m)
+			offset += r
+		} else {
+			Copy(result.Slice(0, rows, offset, offset+c), m)
+			offset += c
+		}
+	}
+	return result
+}
+
+// Split divides a matrix along the given axis into pieces with the given
+// sizes, which must add up to the matrix's rows (for Rows) or cols (for
+// Cols). The pieces are views that share their data with the matrix; see
+// Slice.
+func Split[T Float](m *Dense[T], axis Axis, sizes ...int) []*Dense[T] {
+	axisCheck(axis)
+	length := m.rows
+	if axis == Cols {
+		length = m.cols
+	}
+	total := 0
+	for _, size := range sizes {
+		if size <= 0 {
+			panic(fmt.Errorf("matrix: split sizes (%v) must be greater than 0", sizes))
+		}
+		total += size
+	}
+	if total != length {
+		err := fmt.Errorf(
+			"%w: split sizes (%v) must add up to %d to split a matrix (%dx%d) along %v",
+			ErrDimensionMismatch, sizes, length, m.rows, m.cols, axis,
+		)
+		panic(err)
+	}
+
+	result := make([]*Dense[T], 0, len(sizes))
+	offset := 0
+	for _, size := range sizes {
+		if axis == Rows {
+			result = append(result, m.Slice(offset, offset+size, 0, m.cols))
+		} else {
+			result = append(result, m.Slice(0, m.rows, offset, offset+size))
+		}
+		offset += size
+	}
+	return result
+}
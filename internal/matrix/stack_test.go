@@ -0,0 +1,89 @@
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestStack(t *testing.T) {
+	a := matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2)
+	b := matrix.NewFromSlice([]float64{5, 6}, 2, 1)
+	c := matrix.NewFromSlice([]float64{7, 8}, 1, 2)
+	tests := []struct {
+		name     string
+		result   *matrix.Matrix
+		expected string
+	}{
+		{"HStack", matrix.HStack(a, b), "1 2 5\n3 4 6"},
+		{"VStack", matrix.VStack(a, c), "1 2\n3 4\n7 8"},
+		{"Concat Cols", matrix.Concat(matrix.Cols, b, a, b), "5 1 2 5\n6 3 4 6"},
+		{"Concat Rows", matrix.Concat(matrix.Rows, c, c), "7 8\n7 8"},
+		{"Single matrix", matrix.HStack(a), "1 2\n3 4"},
+		{"Transposed operand", matrix.VStack(a, b.Transpose()), "1 2\n3 4\n5 6"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.result.String() != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, test.result)
+			}
+		})
+	}
+
+	t.Run("Mismatched dimensions", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected HStack to panic when given matrices with different rows")
+			}
+		}()
+		matrix.HStack(a, c)
+	})
+}
+
+func TestSplit(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, 3, 3)
+	tests := []struct {
+		name     string
+		axis     matrix.Axis
+		sizes    []int
+		expected []string
+	}{
+		{"Rows", matrix.Rows, []int{1, 2}, []string{"1 2 3", "4 5 6\n7 8 9"}},
+		{"Cols", matrix.Cols, []int{2, 1}, []string{"1 2\n4 5\n7 8", "3\n6\n9"}},
+		{"Whole", matrix.Cols, []int{3}, []string{"1 2 3\n4 5 6\n7 8 9"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pieces := matrix.Split(m, test.axis, test.sizes...)
+			if len(pieces) != len(test.expected) {
+				t.Fatalf("expected %d pieces, instead got %d", len(test.expected), len(pieces))
+			}
+			for i, piece := range pieces {
+				if piece.String() != test.expected[i] {
+					t.Fatalf(
+						"expected piece %d to be:\n\n%s\n\ninstead got:\n\n%s",
+						i,
+						test.expected[i],
+						piece,
+					)
+				}
+			}
+		})
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		joined := matrix.Concat(matrix.Cols, matrix.Split(m, matrix.Cols, 1, 1, 1)...)
+		if joined.String() != m.String() {
+			t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", m, joined)
+		}
+	})
+
+	t.Run("Sizes must cover the matrix", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected Split to panic when the sizes do not add up")
+			}
+		}()
+		matrix.Split(m, matrix.Rows, 1, 1)
+	})
+}
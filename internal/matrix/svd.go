@@ -0,0 +1,209 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// SVD is the thin singular value decomposition of a matrix A:
+// A = U * diag(values) * Vᵀ, where U and V have orthonormal columns. If A is
+// rows x cols and k is the smaller of the two, U is rows x k, V is cols x k
+// and there are k singular values, which are in descending order.
+type SVD[T Float] struct {
+	values []T
+	u, v   *Dense[T]
+}
+
+// NewSVD calculates the singular value decomposition of a matrix with the
+// one-sided Jacobi algorithm, which is accurate even for tiny singular
+// values. The error wraps ErrNoConvergence in the unlikely case that it
+// fails to converge.
+func NewSVD[T Float](a *Dense[T]) (*SVD[T], error) {
+	// The algorithm needs at least as many rows as cols, and the SVD of the
+	// transpose is the same with U and V swapped.
+	if a.rows < a.cols {
+		d, err := NewSVD(a.Transpose())
+		if err != nil {
+			return nil, err
+		}
+		d.u, d.v = d.v, d.u
+		return d, nil
+	}
+
+	// Orthogonalize the columns of A * V by rotating pairs of them,
+	// accumulating the rotations in V. Once they are orthogonal, their
+	// lengths are the singular values and their directions are U. Both are
+	// kept transposed, so that the columns are contiguous rows.
+	n := a.cols
+	work := a.Transpose().Clone()
+	vt := Identity[T](n)
+	converged := false
+	for sweep := 0; sweep < jacobiSweeps && !converged; sweep++ {
+		converged = true
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				x, y := work.RowView(p), work.RowView(q)
+				alpha, beta, gamma := Dot(x, x), Dot(y, y), Dot(x, y)
+				if abs(gamma) <= T(epsilon[T]())*T(math.Sqrt(float64(alpha*beta))) {
+					continue
+				}
+				converged = false
+
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (abs(zeta) + T(math.Sqrt(float64(zeta*zeta+1))))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / T(math.Sqrt(float64(t*t+1)))
+				s := t * c
+				rotateRows(work, p, q, c, s)
+				rotateRows(vt, p, q, c, s)
+			}
+		}
+	}
+	if !converged {
+		return nil, fmt.Errorf("%w: no singular value decomposition after %d sweeps", ErrNoConvergence, jacobiSweeps)
+	}
+
+	values := make([]T, n)
+	for i := range values {
+		values[i] = vectorNorm(work.RowView(i), L2)
+	}
+	order := descending(values)
+	values = permute(values, order)
+	u := permuteCols(work.Transpose(), order)
+	v := permuteCols(vt.Transpose(), order)
+
+	for i, value := range values {
+		if value != 0 {
+			ScaleInPlace(u.ColView(i), 1/value)
+			continue
+		}
+		// The column is zero, so it has no direction. Any unit vector
+		// orthogonal to the columns before it keeps U orthonormal.
+		completeBasis(u, i)
+	}
+	return &SVD[T]{values: values, u: u, v: v}, nil
+}
+
+// completeBasis replaces column i of m with a unit vector orthogonal to the
+// columns before it, which must be orthonormal.
+func completeBasis[T Float](m *Dense[T], i int) {
+	col := m.ColView(i)
+	for e := 0; e < m.rows; e++ {
+		Copy(col, NewDense[T](m.rows, 1))
+		col.Set(e, 0, 1)
+		// Orthogonalizing twice keeps the result orthogonal to working
+		// precision.
+		for pass := 0; pass < 2; pass++ {
+			for prev := 0; prev < i; prev++ {
+				q := m.ColView(prev)
+				AddScaled(col, -Dot(q, col), q)
+			}
+		}
+		// At least one standard basis vector keeps half of its length.
+		if length := vectorNorm(col, L2); length > 0.5 {
+			ScaleInPlace(col, 1/length)
+			return
+		}
+	}
+}
+
+// Values returns the singular values in descending order.
+func (d *SVD[T]) Values() []T {
+	result := make([]T, len(d.values))
+	copy(result, d.values)
+	return result
+}
+
+// U returns the left singular vectors as columns.
+func (d *SVD[T]) U() *Dense[T] {
+	return d.u.Clone()
+}
+
+// V returns the right singular vectors as columns.
+func (d *SVD[T]) V() *Dense[T] {
+	return d.v.Clone()
+}
+
+// tolerance returns the default magnitude below which singular values are
+// treated as 0, as used by NumPy's matrix_rank.
+func (d *SVD[T]) tolerance() T {
+	size := d.u.rows
+	if d.v.rows > size {
+		size = d.v.rows
+	}
+	return singularTolerance(size, d.values[0])
+}
+
+// Rank returns the number of singular values that are not negligible next to
+// the largest one, which is the number of linearly independent rows or
+// columns of the matrix.
+func (d *SVD[T]) Rank() int {
+	tolerance := d.tolerance()
+	rank := 0
+	for _, value := range d.values {
+		if value > tolerance {
+			rank++
+		}
+	}
+	return rank
+}
+
+// Cond returns the condition number of the matrix in the L2 norm: the ratio
+// of its largest singular value to its smallest. It is +Inf for a matrix
+// without full rank.
+func (d *SVD[T]) Cond() T {
+	smallest := d.values[len(d.values)-1]
+	if smallest == 0 {
+		return T(math.Inf(1))
+	}
+	return d.values[0] / smallest
+}
+
+// PseudoInverse returns the Moore-Penrose pseudo-inverse of the matrix,
+// treating negligible singular values as 0 (see Rank). For a matrix with
+// full column rank, multiplying b by it gives the least squares solution of
+// A * x = b.
+func (d *SVD[T]) PseudoInverse() *Dense[T] {
+	// A⁺ = V * diag(1 / values) * Uᵀ, skipping the negligible values.
+	rank := d.Rank()
+	if rank == 0 {
+		return NewDense[T](d.v.rows, d.u.rows)
+	}
+	scaled := d.v.Slice(0, d.v.rows, 0, rank).Clone()
+	for i := 0; i < rank; i++ {
+		ScaleInPlace(scaled.ColView(i), 1/d.values[i])
+	}
+	return Multiply(scaled, d.u.Slice(0, d.u.rows, 0, rank).Transpose())
+}
+
+// Rank returns the rank of a matrix, calculated from its singular values.
+// See SVD.Rank.
+func Rank[T Float](a *Dense[T]) (int, error) {
+	d, err := NewSVD(a)
+	if err != nil {
+		return 0, err
+	}
+	return d.Rank(), nil
+}
+
+// Cond returns the condition number of a matrix in the L2 norm. See
+// SVD.Cond.
+func Cond[T Float](a *Dense[T]) (T, error) {
+	d, err := NewSVD(a)
+	if err != nil {
+		return 0, err
+	}
+	return d.Cond(), nil
+}
+
+// PseudoInverse returns the Moore-Penrose pseudo-inverse of a matrix. See
+// SVD.PseudoInverse.
+func PseudoInverse[T Float](a *Dense[T]) (*Dense[T], error) {
+	d, err := NewSVD(a)
+	if err != nil {
+		return nil, err
+	}
+	return d.PseudoInverse(), nil
+}
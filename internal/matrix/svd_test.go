@@ -0,0 +1,151 @@
+package matrix_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestSVD(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name     string
+		a        *matrix.Matrix
+		expected []float64 // nil to only check the decomposition
+	}{
+		{"Diagonal", matrix.NewFromSlice([]float64{2, 0, 0, -5}, 2, 2), []float64{5, 2}},
+		{"Rank 1", matrix.NewFromSlice([]float64{1, 2, 2, 4, 3, 6}, 3, 2), []float64{math.Sqrt(70), 0}},
+		{"Zero", matrix.New(3, 2), []float64{0, 0}},
+		{"Tall", matrix.NewRandomNormalFrom(rng, 10, 4), nil},
+		{"Wide", matrix.NewRandomNormalFrom(rng, 3, 7), nil},
+		{"Square", matrix.NewRandomNormalFrom(rng, 6, 6), nil},
+		{"View", matrix.NewRandomNormalFrom(rng, 8, 8).Slice(1, 6, 2, 5).Transpose(), nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svd, err := matrix.NewSVD(test.a)
+			if err != nil {
+				t.Fatalf("expected NewSVD to succeed, instead got %v", err)
+			}
+			values, u, v := svd.Values(), svd.U(), svd.V()
+			for i, expected := range test.expected {
+				if math.Abs(values[i]-expected) > 1e-12 {
+					t.Fatalf("expected the singular values %v, instead got %v", test.expected, values)
+				}
+			}
+			for i := 1; i < len(values); i++ {
+				if values[i] > values[i-1] || values[i] < 0 {
+					t.Fatalf("expected non-negative singular values in descending order, instead got %v", values)
+				}
+			}
+
+			k := len(values)
+			for _, factor := range []*matrix.Matrix{u, v} {
+				if ftf := matrix.Multiply(factor.Transpose(), factor); !matrix.EqualApprox(ftf, matrix.Identity[float64](k), 1e-12, 0) {
+					t.Fatalf("expected U and V to have orthonormal columns, instead %v", matrix.Diff(ftf, matrix.Identity[float64](k)))
+				}
+			}
+			sigma := matrix.New(k, k)
+			for i, value := range values {
+				sigma.Set(i, i, value)
+			}
+			product := matrix.Multiply(matrix.Multiply(u, sigma), v.Transpose())
+			if !matrix.EqualApprox(product, test.a, 1e-12, 1e-12) {
+				t.Fatalf("expected U * S * Vᵀ to equal A, instead %v", matrix.Diff(product, test.a))
+			}
+		})
+	}
+}
+
+func TestRankCond(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *matrix.Matrix
+		rank int
+		cond float64
+	}{
+		{"Identity", matrix.Identity[float64](4), 4, 1},
+		{"Diagonal", matrix.NewFromSlice([]float64{4, 0, 0, 0.5}, 2, 2), 2, 8},
+		{"Rank 1", matrix.NewFromSlice([]float64{1, 2, 2, 4, 3, 6}, 3, 2), 1, math.Inf(1)},
+		{"Zero", matrix.New(2, 3), 0, math.Inf(1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rank, err := matrix.Rank(test.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rank != test.rank {
+				t.Fatalf("expected a rank of %d, instead got %d", test.rank, rank)
+			}
+			cond, err := matrix.Cond(test.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cond != test.cond && math.Abs(cond-test.cond) > 1e-12*test.cond {
+				t.Fatalf("expected a condition number of %f, instead got %f", test.cond, cond)
+			}
+		})
+	}
+}
+
+func TestPseudoInverse(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name string
+		a    *matrix.Matrix
+	}{
+		{"Square", matrix.NewRandomNormalFrom(rng, 4, 4)},
+		{"Tall", matrix.NewRandomNormalFrom(rng, 6, 3)},
+		{"Wide", matrix.NewRandomNormalFrom(rng, 2, 5)},
+		{"Rank 1", matrix.NewFromSlice([]float64{1, 2, 2, 4, 3, 6}, 3, 2)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pinv, err := matrix.PseudoInverse(test.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The Moore-Penrose conditions that define the pseudo-inverse.
+			a := test.a
+			if apa := matrix.Multiply(matrix.Multiply(a, pinv), a); !matrix.EqualApprox(apa, a, 1e-12, 1e-12) {
+				t.Fatalf("expected A * A⁺ * A to equal A, instead %v", matrix.Diff(apa, a))
+			}
+			if pap := matrix.Multiply(matrix.Multiply(pinv, a), pinv); !matrix.EqualApprox(pap, pinv, 1e-12, 1e-12) {
+				t.Fatalf("expected A⁺ * A * A⁺ to equal A⁺, instead %v", matrix.Diff(pap, pinv))
+			}
+			for _, p := range []*matrix.Matrix{matrix.Multiply(a, pinv), matrix.Multiply(pinv, a)} {
+				if !matrix.EqualApprox(p, p.Transpose(), 1e-12, 1e-12) {
+					t.Fatalf("expected A * A⁺ and A⁺ * A to be symmetric, instead %v", matrix.Diff(p, p.Transpose()))
+				}
+			}
+		})
+	}
+
+	inverse, err := matrix.Inverse(tests[0].a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinv, _ := matrix.PseudoInverse(tests[0].a); !matrix.EqualApprox(pinv, inverse, 1e-10, 1e-10) {
+		t.Fatalf("expected the pseudo-inverse of an invertible matrix to be its inverse, instead %v", matrix.Diff(pinv, inverse))
+	}
+}
+
+func TestSVDFloat32(t *testing.T) {
+	a := matrix.Convert[float32](matrix.NewRandomNormalFrom(rand.New(rand.NewSource(1)), 20, 8))
+	svd, err := matrix.NewSVD(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := svd.Values()
+	sigma := matrix.NewDense[float32](len(values), len(values))
+	for i, value := range values {
+		sigma.Set(i, i, value)
+	}
+	product := matrix.Multiply(matrix.Multiply(svd.U(), sigma), svd.V().Transpose())
+	if !matrix.EqualApprox(product, a, 1e-5, 1e-5) {
+		t.Fatalf("expected U * S * Vᵀ to equal A, instead %v", matrix.Diff(product, a))
+	}
+}
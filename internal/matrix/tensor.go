@@ -0,0 +1,590 @@
+package matrix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tensor is an n-dimensional array, such as a batch x channels x height x
+// width set of images, which a matrix can't express.
+//
+// Like Dense, a tensor keeps a stride for every axis: the entry at the index
+// (i_0, i_1, ...) is stored at data[i_0*strides[0] + i_1*strides[1] + ...].
+// Tensors created by NewTensor are laid out in row-major order, with the
+// last axis contiguous, but views such as Permute share the data of the
+// tensor they came from.
+type Tensor[T Float] struct {
+	shape, strides []int
+	data           []T
+}
+
+func formatShape(shape []int) string {
+	parts := make([]string, len(shape))
+	for i, length := range shape {
+		parts[i] = fmt.Sprint(length)
+	}
+	return strings.Join(parts, "x")
+}
+
+func shapeErr(shape []int) error {
+	if len(shape) == 0 {
+		return fmt.Errorf("%w: a tensor needs at least one axis", ErrInvalidShape)
+	}
+	for _, length := range shape {
+		if length <= 0 {
+			return fmt.Errorf("%w (%s)", ErrInvalidShape, formatShape(shape))
+		}
+	}
+	return nil
+}
+
+// size returns the number of entries in a tensor with the given shape.
+func size(shape []int) int {
+	result := 1
+	for _, length := range shape {
+		result *= length
+	}
+	return result
+}
+
+// rowMajorStrides returns the strides of a tensor with the given shape that
+// is laid out in row-major order.
+func rowMajorStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for axis := len(shape) - 1; axis >= 0; axis-- {
+		strides[axis] = stride
+		stride *= shape[axis]
+	}
+	return strides
+}
+
+// newTensor returns a row-major tensor that uses data, which must have
+// exactly as many entries as the shape.
+func newTensor[T Float](data []T, shape []int) *Tensor[T] {
+	shape = append([]int(nil), shape...)
+	return &Tensor[T]{shape: shape, strides: rowMajorStrides(shape), data: data}
+}
+
+// NewTensor returns a tensor with the given shape with all values set to 0.
+//
+// Will panic if there are no axes or any of them has a length less than or
+// equal to 0.
+func NewTensor[T Float](shape ...int) *Tensor[T] {
+	t, err := TryNewTensor[T](shape...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TryNewTensor is like NewTensor, but returns an error wrapping
+// ErrInvalidShape instead of panicking.
+func TryNewTensor[T Float](shape ...int) (*Tensor[T], error) {
+	if err := shapeErr(shape); err != nil {
+		return nil, err
+	}
+	return newTensor(make([]T, size(shape)), shape), nil
+}
+
+// NewTensorFromSlice returns a tensor with the given shape and all values
+// imported from the supplied slice, which holds them in row-major order.
+func NewTensorFromSlice[T Float](data []T, shape ...int) *Tensor[T] {
+	t, err := TryNewTensorFromSlice(data, shape...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TryNewTensorFromSlice is like NewTensorFromSlice, but returns an error
+// instead of panicking if the shape is invalid (ErrInvalidShape) or the
+// slice doesn't have an entry for every index (ErrInvalidLength).
+func TryNewTensorFromSlice[T Float](data []T, shape ...int) (*Tensor[T], error) {
+	if err := shapeErr(shape); err != nil {
+		return nil, err
+	}
+	if len(data) != size(shape) {
+		return nil, fmt.Errorf(
+			"%w: supplied slice (%T) is expected to have a length of %d, instead its length is %d",
+			ErrInvalidLength,
+			data,
+			size(shape),
+			len(data),
+		)
+	}
+	tensorData := make([]T, len(data))
+	copy(tensorData, data)
+	return newTensor(tensorData, shape), nil
+}
+
+// FromMatrix returns a 2-D view of a matrix as a rows x cols tensor. The
+// view shares its data with the matrix.
+func FromMatrix[T Float](m *Dense[T]) *Tensor[T] {
+	return &Tensor[T]{
+		shape:   []int{m.rows, m.cols},
+		strides: []int{m.rowStride, m.colStride},
+		data:    m.data,
+	}
+}
+
+// Matrix returns a view of a 2-D tensor as a matrix, which shares its data
+// with the tensor. It panics if the tensor doesn't have exactly 2 axes.
+func (t *Tensor[T]) Matrix() *Dense[T] {
+	m, err := t.TryMatrix()
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// TryMatrix is like Matrix, but returns an error wrapping ErrInvalidShape
+// instead of panicking.
+func (t *Tensor[T]) TryMatrix() (*Dense[T], error) {
+	if len(t.shape) != 2 {
+		return nil, fmt.Errorf("%w: only a tensor with 2 axes is a matrix (%s)", ErrInvalidShape, formatShape(t.shape))
+	}
+	return &Dense[T]{
+		rows:      t.shape[0],
+		cols:      t.shape[1],
+		rowStride: t.strides[0],
+		colStride: t.strides[1],
+		data:      t.data,
+	}, nil
+}
+
+// Shape returns the length of each axis of the tensor.
+func (t *Tensor[T]) Shape() []int {
+	return append([]int(nil), t.shape...)
+}
+
+// Strides returns how far apart consecutive entries along each axis of the
+// tensor are stored.
+func (t *Tensor[T]) Strides() []int {
+	return append([]int(nil), t.strides...)
+}
+
+// NDim returns the number of axes the tensor has.
+func (t *Tensor[T]) NDim() int {
+	return len(t.shape)
+}
+
+// Size returns the number of entries in the tensor.
+func (t *Tensor[T]) Size() int {
+	return size(t.shape)
+}
+
+func (t *Tensor[T]) offsetErr(index []int) (int, error) {
+	if len(index) != len(t.shape) {
+		return 0, fmt.Errorf(
+			"%w: a tensor with %d axes needs %d indices, instead there are %d",
+			ErrOutOfRange, len(t.shape), len(t.shape), len(index),
+		)
+	}
+	offset := 0
+	for axis, i := range index {
+		if i < 0 || i >= t.shape[axis] {
+			return 0, fmt.Errorf(
+				"%w: index %v is outside of a tensor with shape %s",
+				ErrOutOfRange, index, formatShape(t.shape),
+			)
+		}
+		offset += i * t.strides[axis]
+	}
+	return offset, nil
+}
+
+func (t *Tensor[T]) offset(index []int) int {
+	offset, err := t.offsetErr(index)
+	if err != nil {
+		panic(err)
+	}
+	return offset
+}
+
+// Get returns the value at the given index, which needs one entry for each
+// axis.
+func (t *Tensor[T]) Get(index ...int) T {
+	return t.data[t.offset(index)]
+}
+
+// Set sets the value at the given index, which needs one entry for each
+// axis.
+func (t *Tensor[T]) Set(value T, index ...int) {
+	t.data[t.offset(index)] = value
+}
+
+// TryGet is like Get, but returns an error wrapping ErrOutOfRange instead of
+// panicking if the index is outside of the tensor.
+func (t *Tensor[T]) TryGet(index ...int) (T, error) {
+	offset, err := t.offsetErr(index)
+	if err != nil {
+		return 0, err
+	}
+	return t.data[offset], nil
+}
+
+// TrySet is like Set, but returns an error wrapping ErrOutOfRange instead of
+// panicking if the index is outside of the tensor.
+func (t *Tensor[T]) TrySet(value T, index ...int) error {
+	offset, err := t.offsetErr(index)
+	if err != nil {
+		return err
+	}
+	t.data[offset] = value
+	return nil
+}
+
+// walk calls f with every index of a tensor with the given shape, in
+// row-major order, along with the offset of that index into the data of
+// each tensor whose strides are given. f must not modify either slice.
+func walk(shape []int, strides [][]int, f func(index, offsets []int)) {
+	index := make([]int, len(shape))
+	offsets := make([]int, len(strides))
+	for {
+		f(index, offsets)
+		axis := len(shape) - 1
+		for ; axis >= 0; axis-- {
+			index[axis]++
+			for i, s := range strides {
+				offsets[i] += s[axis]
+			}
+			if index[axis] < shape[axis] {
+				break
+			}
+			for i, s := range strides {
+				offsets[i] -= s[axis] * shape[axis]
+			}
+			index[axis] = 0
+		}
+		if axis < 0 {
+			return
+		}
+	}
+}
+
+// Do calls f with every index of the tensor and the value there, in
+// row-major order. f must not modify the index.
+func (t *Tensor[T]) Do(f func(index []int, value T)) {
+	walk(t.shape, [][]int{t.strides}, func(index, offsets []int) {
+		f(index, t.data[offsets[0]])
+	})
+}
+
+// Values returns the entries of the tensor in row-major order.
+func (t *Tensor[T]) Values() []T {
+	result := make([]T, 0, t.Size())
+	t.Do(func(_ []int, value T) {
+		result = append(result, value)
+	})
+	return result
+}
+
+// Clone returns a row-major copy of the tensor that does not share its data
+// with any other tensor.
+func (t *Tensor[T]) Clone() *Tensor[T] {
+	return newTensor(t.Values(), t.shape)
+}
+
+func (t *Tensor[T]) isRowMajor() bool {
+	expected := rowMajorStrides(t.shape)
+	for axis, stride := range t.strides {
+		if t.shape[axis] != 1 && stride != expected[axis] {
+			return false
+		}
+	}
+	return true
+}
+
+// Reshape returns the entries of the tensor, in row-major order, arranged
+// into the given shape, which must have the same number of entries. One
+// axis may be given as -1, in which case its length is inferred from the
+// others. The result is a view that shares its data with the tensor when
+// the tensor is laid out in row-major order, and a copy otherwise.
+func (t *Tensor[T]) Reshape(shape ...int) *Tensor[T] {
+	result, err := t.TryReshape(shape...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// TryReshape is like Reshape, but returns an error wrapping ErrInvalidShape
+// instead of panicking.
+func (t *Tensor[T]) TryReshape(shape ...int) (*Tensor[T], error) {
+	shape = append([]int(nil), shape...)
+	inferred := -1
+	known := 1
+	for axis, length := range shape {
+		if length == -1 && inferred == -1 {
+			inferred = axis
+			continue
+		}
+		known *= length
+	}
+	if inferred != -1 && known > 0 && t.Size()%known == 0 {
+		shape[inferred] = t.Size() / known
+	}
+	if err := shapeErr(shape); err != nil {
+		return nil, err
+	}
+	if size(shape) != t.Size() {
+		return nil, fmt.Errorf(
+			"%w: a tensor with shape %s cannot be reshaped to %s",
+			ErrInvalidShape, formatShape(t.shape), formatShape(shape),
+		)
+	}
+	if !t.isRowMajor() {
+		t = t.Clone()
+	}
+	return newTensor(t.data, shape), nil
+}
+
+// Permute returns a view of the tensor with its axes rearranged, so that
+// axis i of the view is axis axes[i] of the tensor. For example,
+// Permute(0, 2, 3, 1) turns batch x channels x height x width images into
+// batch x height x width x channels. The view shares its data with the
+// tensor.
+func (t *Tensor[T]) Permute(axes ...int) *Tensor[T] {
+	result, err := t.TryPermute(axes...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// TryPermute is like Permute, but returns an error wrapping ErrInvalidAxes
+// instead of panicking if the axes are not a permutation of the tensor's
+// axes.
+func (t *Tensor[T]) TryPermute(axes ...int) (*Tensor[T], error) {
+	invalid := len(axes) != len(t.shape)
+	seen := make([]bool, len(t.shape))
+	for _, axis := range axes {
+		if invalid || axis < 0 || axis >= len(t.shape) || seen[axis] {
+			return nil, fmt.Errorf(
+				"%w: %v is not a permutation of the axes of a tensor with %d axes",
+				ErrInvalidAxes, axes, len(t.shape),
+			)
+		}
+		seen[axis] = true
+	}
+	result := &Tensor[T]{
+		shape:   make([]int, len(axes)),
+		strides: make([]int, len(axes)),
+		data:    t.data,
+	}
+	for i, axis := range axes {
+		result.shape[i] = t.shape[axis]
+		result.strides[i] = t.strides[axis]
+	}
+	return result, nil
+}
+
+// Transpose returns a view of the tensor with the order of its axes
+// reversed, which for a 2-D tensor is the transpose of the matrix. The view
+// shares its data with the tensor.
+func (t *Tensor[T]) Transpose() *Tensor[T] {
+	axes := make([]int, len(t.shape))
+	for i := range axes {
+		axes[i] = len(axes) - 1 - i
+	}
+	return t.Permute(axes...)
+}
+
+// broadcastShapes returns the shape that results from broadcasting tensors
+// with the given shapes together.
+func broadcastShapes(first, second []int) ([]int, error) {
+	if len(first) < len(second) {
+		first, second = second, first
+	}
+	result := append([]int(nil), first...)
+	offset := len(first) - len(second)
+	for i, length := range second {
+		switch result[offset+i] {
+		case length:
+		case 1:
+			result[offset+i] = length
+		default:
+			if length != 1 {
+				return nil, newShapeError(
+					first, second,
+					"the shapes of the supplied tensors cannot be broadcast together; "+
+						"each axis must either be equal or 1",
+				)
+			}
+		}
+	}
+	return result, nil
+}
+
+// broadcastView returns a read-only view of t stretched to a shape that it
+// is known to broadcast to. The view must never be written to.
+func broadcastView[T Float](t *Tensor[T], shape []int) *Tensor[T] {
+	offset := len(shape) - len(t.shape)
+	view := &Tensor[T]{
+		shape:   append([]int(nil), shape...),
+		strides: make([]int, len(shape)),
+		data:    t.data,
+	}
+	for axis, length := range t.shape {
+		if length == shape[offset+axis] {
+			view.strides[offset+axis] = t.strides[axis]
+		}
+	}
+	return view
+}
+
+// BroadcastTo returns a view of the tensor stretched to the given shape in
+// the manner of NumPy: the shapes are aligned at their last axes, the
+// tensor gets new leading axes of length 1 if it has fewer, and every axis
+// of length 1 is repeated to match the shape. The view shares its data with
+// the tensor and must never be written to, since many of its entries are
+// the same one.
+func (t *Tensor[T]) BroadcastTo(shape ...int) *Tensor[T] {
+	result, err := t.TryBroadcastTo(shape...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// TryBroadcastTo is like BroadcastTo, but returns an error instead of
+// panicking if the shape is invalid (ErrInvalidShape) or the tensor can't
+// be broadcast to it (a *ShapeError).
+func (t *Tensor[T]) TryBroadcastTo(shape ...int) (*Tensor[T], error) {
+	if err := shapeErr(shape); err != nil {
+		return nil, err
+	}
+	result, err := broadcastShapes(t.shape, shape)
+	if err != nil || len(result) != len(shape) || size(result) != size(shape) {
+		return nil, newShapeError(t.shape, shape, "the tensor cannot be broadcast to the shape")
+	}
+	return broadcastView(t, shape), nil
+}
+
+// ZipTensor runs the given function on every pair of corresponding entries
+// in the two tensors and returns the result. The tensors are broadcast
+// together (see BroadcastTo), so for example a channels x 1 x 1 tensor can
+// be added to every pixel of a batch x channels x height x width one. It
+// panics with a *ShapeError if they can't be.
+func ZipTensor[T Float](first, second *Tensor[T], function func(x, y T) T) *Tensor[T] {
+	result, err := TryZipTensor(first, second, function)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// TryZipTensor is like ZipTensor, but returns a *ShapeError instead of
+// panicking if the tensors cannot be broadcast together.
+func TryZipTensor[T Float](first, second *Tensor[T], function func(x, y T) T) (*Tensor[T], error) {
+	shape, err := broadcastShapes(first.shape, second.shape)
+	if err != nil {
+		return nil, err
+	}
+	first, second = broadcastView(first, shape), broadcastView(second, shape)
+	result := NewTensor[T](shape...)
+	walk(shape, [][]int{result.strides, first.strides, second.strides}, func(_, offsets []int) {
+		result.data[offsets[0]] = function(first.data[offsets[1]], second.data[offsets[2]])
+	})
+	return result, nil
+}
+
+// AddTensor adds two tensors together and returns the result. The tensors
+// are broadcast together, as in ZipTensor.
+func AddTensor[T Float](first, second *Tensor[T]) *Tensor[T] {
+	return ZipTensor(first, second, func(x, y T) T { return x + y })
+}
+
+// TryAddTensor is like AddTensor, but returns a *ShapeError instead of
+// panicking if the tensors cannot be broadcast together.
+func TryAddTensor[T Float](first, second *Tensor[T]) (*Tensor[T], error) {
+	return TryZipTensor(first, second, func(x, y T) T { return x + y })
+}
+
+// SubTensor subtracts the second tensor from the first and returns the
+// result. The tensors are broadcast together, as in ZipTensor.
+func SubTensor[T Float](first, second *Tensor[T]) *Tensor[T] {
+	return ZipTensor(first, second, func(x, y T) T { return x - y })
+}
+
+// TrySubTensor is like SubTensor, but returns a *ShapeError instead of
+// panicking if the tensors cannot be broadcast together.
+func TrySubTensor[T Float](first, second *Tensor[T]) (*Tensor[T], error) {
+	return TryZipTensor(first, second, func(x, y T) T { return x - y })
+}
+
+// HadamardTensor multiplies the corresponding entries of two tensors
+// together and returns the result. The tensors are broadcast together, as
+// in ZipTensor.
+func HadamardTensor[T Float](first, second *Tensor[T]) *Tensor[T] {
+	return ZipTensor(first, second, func(x, y T) T { return x * y })
+}
+
+// TryHadamardTensor is like HadamardTensor, but returns a *ShapeError
+// instead of panicking if the tensors cannot be broadcast together.
+func TryHadamardTensor[T Float](first, second *Tensor[T]) (*Tensor[T], error) {
+	return TryZipTensor(first, second, func(x, y T) T { return x * y })
+}
+
+// DivTensor divides the entries of the first tensor by the corresponding
+// entries of the second and returns the result. The tensors are broadcast
+// together, as in ZipTensor.
+func DivTensor[T Float](first, second *Tensor[T]) *Tensor[T] {
+	return ZipTensor(first, second, func(x, y T) T { return x / y })
+}
+
+// TryDivTensor is like DivTensor, but returns a *ShapeError instead of
+// panicking if the tensors cannot be broadcast together.
+func TryDivTensor[T Float](first, second *Tensor[T]) (*Tensor[T], error) {
+	return TryZipTensor(first, second, func(x, y T) T { return x / y })
+}
+
+// MapTensor runs the given function on every entry in the tensor and
+// returns the result.
+func MapTensor[T Float](t *Tensor[T], function func(T) T) *Tensor[T] {
+	result := NewTensor[T](t.shape...)
+	walk(t.shape, [][]int{result.strides, t.strides}, func(_, offsets []int) {
+		result.data[offsets[0]] = function(t.data[offsets[1]])
+	})
+	return result
+}
+
+// ScaleTensor multiplies every entry in the tensor by the scalar and
+// returns the result.
+func ScaleTensor[T Float](t *Tensor[T], scalar T) *Tensor[T] {
+	return MapTensor(t, func(x T) T { return x * scalar })
+}
+
+// String formats a tensor with up to 2 axes like a matrix. A tensor with
+// more axes is formatted as each of the matrices made by its last 2 axes,
+// headed by their index, such as (0, 1, :, :).
+func (t *Tensor[T]) String() string {
+	n := len(t.shape)
+	if n == 1 {
+		return (&Dense[T]{rows: 1, cols: t.shape[0], colStride: t.strides[0], data: t.data}).String()
+	}
+	if n == 2 {
+		return t.Matrix().String()
+	}
+	sb := strings.Builder{}
+	walk(t.shape[:n-2], [][]int{t.strides[:n-2]}, func(index, offsets []int) {
+		if sb.Len() != 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteRune('(')
+		for _, i := range index {
+			fmt.Fprintf(&sb, "%d, ", i)
+		}
+		sb.WriteString(":, :)\n")
+		m := &Dense[T]{
+			rows:      t.shape[n-2],
+			cols:      t.shape[n-1],
+			rowStride: t.strides[n-2],
+			colStride: t.strides[n-1],
+			data:      t.data[offsets[0]:],
+		}
+		sb.WriteString(m.String())
+	})
+	return sb.String()
+}
@@ -0,0 +1,238 @@
+package matrix_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+// arange returns a tensor with the given shape whose entries count up from
+// 0 in row-major order.
+func arange(shape ...int) *matrix.Tensor[float64] {
+	size := 1
+	for _, length := range shape {
+		size *= length
+	}
+	data := make([]float64, size)
+	for i := range data {
+		data[i] = float64(i)
+	}
+	return matrix.NewTensorFromSlice(data, shape...)
+}
+
+func TestNewTensor(t *testing.T) {
+	tensor := arange(2, 3, 4)
+	if shape := tensor.Shape(); !reflect.DeepEqual(shape, []int{2, 3, 4}) {
+		t.Fatalf("expected shape [2 3 4], instead got %v", shape)
+	}
+	if strides := tensor.Strides(); !reflect.DeepEqual(strides, []int{12, 4, 1}) {
+		t.Fatalf("expected strides [12 4 1], instead got %v", strides)
+	}
+	if tensor.NDim() != 3 || tensor.Size() != 24 {
+		t.Fatalf("expected 3 axes and 24 entries, instead got %d and %d", tensor.NDim(), tensor.Size())
+	}
+	if v := tensor.Get(1, 2, 3); v != 23 {
+		t.Fatalf("expected 23 at (1, 2, 3), instead got %f", v)
+	}
+	tensor.Set(-1, 1, 0, 2)
+	if v := tensor.Get(1, 0, 2); v != -1 {
+		t.Fatalf("expected -1 at (1, 0, 2) after setting it, instead got %f", v)
+	}
+}
+
+func TestTensorErrors(t *testing.T) {
+	tensor := arange(2, 3)
+	tests := []struct {
+		name string
+		fn   func() error
+		want error
+	}{
+		{"No axes", func() error { _, err := matrix.TryNewTensor[float64](); return err }, matrix.ErrInvalidShape},
+		{"Zero axis", func() error { _, err := matrix.TryNewTensor[float64](2, 0); return err }, matrix.ErrInvalidShape},
+		{"Wrong length", func() error { _, err := matrix.TryNewTensorFromSlice([]float64{1, 2}, 3); return err }, matrix.ErrInvalidLength},
+		{"Too few indices", func() error { _, err := tensor.TryGet(1); return err }, matrix.ErrOutOfRange},
+		{"Index out of range", func() error { return tensor.TrySet(1, 0, 3) }, matrix.ErrOutOfRange},
+		{"Reshape size", func() error { _, err := tensor.TryReshape(4, 2); return err }, matrix.ErrInvalidShape},
+		{"Reshape two inferred", func() error { _, err := tensor.TryReshape(-1, -1); return err }, matrix.ErrInvalidShape},
+		{"Reshape uneven", func() error { _, err := tensor.TryReshape(4, -1); return err }, matrix.ErrInvalidShape},
+		{"Permute repeat", func() error { _, err := tensor.TryPermute(0, 0); return err }, matrix.ErrInvalidAxes},
+		{"Permute too few", func() error { _, err := tensor.TryPermute(1); return err }, matrix.ErrInvalidAxes},
+		{"Broadcast", func() error { _, err := tensor.TryBroadcastTo(2, 4); return err }, matrix.ErrDimensionMismatch},
+		{"Broadcast fewer axes", func() error { _, err := tensor.TryBroadcastTo(3); return err }, matrix.ErrDimensionMismatch},
+		{"Add", func() error { _, err := matrix.TryAddTensor(tensor, arange(2)); return err }, matrix.ErrDimensionMismatch},
+		{"Matrix", func() error { _, err := arange(2, 3, 4).TryMatrix(); return err }, matrix.ErrInvalidShape},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.fn(); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+
+	_, err := matrix.TryAddTensor(arange(2, 3, 4), arange(3, 2))
+	var shapeErr *matrix.ShapeError
+	if !errors.As(err, &shapeErr) {
+		t.Fatalf("expected a *ShapeError, instead got %v", err)
+	}
+	if !reflect.DeepEqual(shapeErr.First, []int{2, 3, 4}) || !reflect.DeepEqual(shapeErr.Second, []int{3, 2}) {
+		t.Fatalf("expected the shapes 2x3x4 and 3x2, instead got %v", shapeErr)
+	}
+}
+
+func TestReshape(t *testing.T) {
+	tests := []struct {
+		name     string
+		tensor   *matrix.Tensor[float64]
+		shape    []int
+		expected []int
+	}{
+		{"Flatten", arange(2, 3, 4), []int{24}, []int{24}},
+		{"Inferred", arange(2, 3, 4), []int{-1, 4}, []int{6, 4}},
+		{"Add axes", arange(6), []int{1, 2, 1, 3}, []int{1, 2, 1, 3}},
+		{"Permuted", arange(2, 3, 4).Permute(2, 0, 1), []int{4, -1}, []int{4, 6}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.tensor.Reshape(test.shape...)
+			if shape := result.Shape(); !reflect.DeepEqual(shape, test.expected) {
+				t.Fatalf("expected shape %v, instead got %v", test.expected, shape)
+			}
+			if !reflect.DeepEqual(result.Values(), test.tensor.Values()) {
+				t.Fatalf("expected the entries to stay in row-major order %v, instead got %v", test.tensor.Values(), result.Values())
+			}
+		})
+	}
+
+	tensor := arange(2, 3)
+	tensor.Reshape(3, 2).Set(100, 0, 0)
+	if v := tensor.Get(0, 0); v != 100 {
+		t.Fatalf("expected reshaping a row-major tensor to share its data, instead (0, 0) is %f", v)
+	}
+	tensor.Transpose().Reshape(6).Set(-100, 0)
+	if v := tensor.Get(0, 0); v != 100 {
+		t.Fatalf("expected reshaping a transposed tensor to copy it, instead (0, 0) is %f", v)
+	}
+}
+
+func TestPermute(t *testing.T) {
+	tensor := arange(2, 3, 4)
+	permuted := tensor.Permute(1, 2, 0)
+	if shape := permuted.Shape(); !reflect.DeepEqual(shape, []int{3, 4, 2}) {
+		t.Fatalf("expected shape [3 4 2], instead got %v", shape)
+	}
+	tensor.Do(func(index []int, value float64) {
+		if v := permuted.Get(index[1], index[2], index[0]); v != value {
+			t.Fatalf("expected %f at %v of the permutation, instead got %f", value, index, v)
+		}
+	})
+
+	transposed := tensor.Transpose()
+	if shape := transposed.Shape(); !reflect.DeepEqual(shape, []int{4, 3, 2}) {
+		t.Fatalf("expected the transpose to have shape [4 3 2], instead got %v", shape)
+	}
+	transposed.Set(-1, 3, 2, 1)
+	if v := tensor.Get(1, 2, 3); v != -1 {
+		t.Fatalf("expected the transpose to share its data, instead (1, 2, 3) is %f", v)
+	}
+
+	clone := permuted.Clone()
+	if !reflect.DeepEqual(clone.Strides(), []int{8, 2, 1}) || !reflect.DeepEqual(clone.Values(), permuted.Values()) {
+		t.Fatalf("expected a row-major clone with the same entries, instead got strides %v and entries %v", clone.Strides(), clone.Values())
+	}
+}
+
+func TestTensorBroadcast(t *testing.T) {
+	tests := []struct {
+		name          string
+		first, second *matrix.Tensor[float64]
+		shape         []int
+	}{
+		{"Same shape", arange(2, 3), arange(2, 3), []int{2, 3}},
+		{"Scalar", arange(2, 3, 4), matrix.NewTensorFromSlice([]float64{10}, 1), []int{2, 3, 4}},
+		{"Channel bias", arange(2, 3, 2, 2), arange(3, 1, 1), []int{2, 3, 2, 2}},
+		{"Both stretched", arange(3, 1), arange(1, 4), []int{3, 4}},
+		{"Fewer axes first", arange(4), arange(2, 3, 4), []int{2, 3, 4}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sum := matrix.AddTensor(test.first, test.second)
+			if shape := sum.Shape(); !reflect.DeepEqual(shape, test.shape) {
+				t.Fatalf("expected shape %v, instead got %v", test.shape, shape)
+			}
+			first, second := test.first.BroadcastTo(test.shape...), test.second.BroadcastTo(test.shape...)
+			sum.Do(func(index []int, value float64) {
+				if expected := first.Get(index...) + second.Get(index...); value != expected {
+					t.Fatalf("expected %f at %v, instead got %f", expected, index, value)
+				}
+			})
+		})
+	}
+}
+
+func TestTensorElementWise(t *testing.T) {
+	first := arange(2, 2, 3)
+	second := matrix.MapTensor(arange(2, 2, 3), func(x float64) float64 { return x + 1 })
+	tests := []struct {
+		name   string
+		result *matrix.Tensor[float64]
+		fn     func(x, y float64) float64
+	}{
+		{"AddTensor", matrix.AddTensor(first, second), func(x, y float64) float64 { return x + y }},
+		{"SubTensor", matrix.SubTensor(first, second), func(x, y float64) float64 { return x - y }},
+		{"HadamardTensor", matrix.HadamardTensor(first, second), func(x, y float64) float64 { return x * y }},
+		{"DivTensor", matrix.DivTensor(first, second), func(x, y float64) float64 { return x / y }},
+		{"ScaleTensor", matrix.ScaleTensor(first, 3), func(x, _ float64) float64 { return 3 * x }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.result.Do(func(index []int, value float64) {
+				if expected := test.fn(first.Get(index...), second.Get(index...)); value != expected {
+					t.Fatalf("expected %f at %v, instead got %f", expected, index, value)
+				}
+			})
+		})
+	}
+}
+
+func TestTensorMatrix(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	tensor := matrix.FromMatrix(m.Transpose())
+	if shape := tensor.Shape(); !reflect.DeepEqual(shape, []int{3, 2}) {
+		t.Fatalf("expected shape [3 2], instead got %v", shape)
+	}
+	if v := tensor.Get(2, 1); v != 6 {
+		t.Fatalf("expected 6 at (2, 1), instead got %f", v)
+	}
+	tensor.Set(-1, 0, 1)
+	if v := m.Get(1, 0); v != -1 {
+		t.Fatalf("expected the tensor to share its data with the matrix, instead (1, 0) is %f", v)
+	}
+
+	back := matrix.AddTensor(tensor, matrix.NewTensorFromSlice([]float64{10, 20}, 2)).Matrix()
+	expected := matrix.Add(m.Transpose(), matrix.NewFromSlice([]float64{10, 20}, 1, 2))
+	if !matrix.Equal(back, expected) {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, back)
+	}
+}
+
+func TestTensorString(t *testing.T) {
+	tests := []struct {
+		tensor   *matrix.Tensor[float64]
+		expected string
+	}{
+		{arange(3), "0 1 2"},
+		{arange(2, 2), "0 1\n2 3"},
+		{arange(2, 1, 2, 2), "(0, 0, :, :)\n0 1\n2 3\n\n(1, 0, :, :)\n4 5\n6 7"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.tensor.Shape()), func(t *testing.T) {
+			if s := test.tensor.String(); s != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, s)
+			}
+		})
+	}
+}
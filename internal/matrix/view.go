@@ -0,0 +1,49 @@
+package matrix
+
+import "fmt"
+
+// Slice returns a view of the rows [r0, r1) and columns [c0, c1) of the
+// matrix. The view shares its data with the matrix, so no copy is made and
+// changes to either matrix are visible through the other. Use Clone to get
+// an independent copy.
+func (m *Dense[T]) Slice(r0, r1, c0, c1 int) *Dense[T] {
+	if r0 < 0 || c0 < 0 || r1 > m.rows || c1 > m.cols || r0 >= r1 || c0 >= c1 {
+		err := fmt.Errorf(
+			"%w: slice [%d:%d, %d:%d] is out of range for a matrix with dimensions (%dx%d)",
+			ErrOutOfRange,
+			r0, r1, c0, c1,
+			m.rows,
+			m.cols,
+		)
+		panic(err)
+	}
+	return &Dense[T]{
+		rows:      r1 - r0,
+		cols:      c1 - c0,
+		rowStride: m.rowStride,
+		colStride: m.colStride,
+		data:      m.data[m.index(r0, c0):],
+	}
+}
+
+// RowView returns a 1 x cols view of the given row. Like Slice, the view
+// shares its data with the matrix.
+func (m *Dense[T]) RowView(row int) *Dense[T] {
+	m.rowCheck(row)
+	return m.Slice(row, row+1, 0, m.cols)
+}
+
+// ColView returns a rows x 1 view of the given column. Like Slice, the view
+// shares its data with the matrix.
+func (m *Dense[T]) ColView(col int) *Dense[T] {
+	m.colCheck(col)
+	return m.Slice(0, m.rows, col, col+1)
+}
+
+// Clone returns a copy of the matrix that does not share its data with any
+// other matrix.
+func (m *Dense[T]) Clone() *Dense[T] {
+	result := NewDense[T](m.rows, m.cols)
+	Copy(result, m)
+	return result
+}
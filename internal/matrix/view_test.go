@@ -0,0 +1,200 @@
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+func TestSlice(t *testing.T) {
+	tests := []struct {
+		name           string
+		r0, r1, c0, c1 int
+		expected       string
+	}{
+		{"Whole matrix", 0, 3, 0, 3, "1 2 3\n4 5 6\n7 8 9"},
+		{"Top left", 0, 2, 0, 2, "1 2\n4 5"},
+		{"Bottom right", 1, 3, 1, 3, "5 6\n8 9"},
+		{"Middle column", 0, 3, 1, 2, "2\n5\n8"},
+		{"Single entry", 2, 3, 0, 1, "7"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, 3, 3)
+			view := m.Slice(test.r0, test.r1, test.c0, test.c1)
+			if view.String() != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, view)
+			}
+		})
+	}
+
+	t.Run("Shares data", func(t *testing.T) {
+		m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, 3, 3)
+		view := m.Slice(1, 3, 1, 3)
+		view.Set(0, 0, 50)
+		if m.Get(1, 1) != 50 {
+			t.Fatalf("expected a change to the view to be visible in the matrix")
+		}
+		m.Set(2, 2, 90)
+		if view.Get(1, 1) != 90 {
+			t.Fatalf("expected a change to the matrix to be visible in the view")
+		}
+	})
+
+	t.Run("Slice of transpose", func(t *testing.T) {
+		m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+		view := m.Transpose().Slice(1, 3, 0, 2)
+		expected := "2 5\n3 6"
+		if view.String() != expected {
+			t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, view)
+		}
+	})
+
+	t.Run("Out of range", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected Slice to panic when given an out of range slice")
+			}
+		}()
+		matrix.New(2, 2).Slice(0, 3, 0, 1)
+	})
+}
+
+func TestRowColView(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6}, 2, 3)
+	row := m.RowView(1)
+	col := m.ColView(2)
+	if row.String() != "4 5 6" {
+		t.Fatalf("expected row 1 to be \"4 5 6\", instead got %q", row)
+	}
+	if col.String() != "3\n6" {
+		t.Fatalf("expected col 2 to be \"3\\n6\", instead got %q", col)
+	}
+
+	matrix.ScaleInPlace(row, 10)
+	expected := "1 2 3\n40 50 60"
+	if m.String() != expected {
+		t.Fatalf("expected scaling a row view to update the matrix to:\n\n%s\n\ninstead got:\n\n%s", expected, m)
+	}
+}
+
+func TestViewOperations(t *testing.T) {
+	m := matrix.NewFromSlice([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, 3, 3)
+	top := m.Slice(0, 2, 0, 3)
+	left := m.Slice(0, 3, 0, 2)
+
+	product := matrix.Multiply(top, left)
+	expected := "30 36\n66 81"
+	if product.String() != expected {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, product)
+	}
+
+	t.Run("Disjoint views as destination", func(t *testing.T) {
+		buffer := matrix.New(4, 2)
+		matrix.MulInto(buffer.Slice(0, 2, 0, 2), top, left)
+		matrix.MulInto(buffer.Slice(2, 4, 0, 2), top, left)
+		expected := "30 36\n66 81\n30 36\n66 81"
+		if buffer.String() != expected {
+			t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, buffer)
+		}
+	})
+
+	t.Run("Overlapping destination", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected MulInto to panic when the destination overlaps an operand")
+			}
+		}()
+		matrix.MulInto(m.Slice(1, 3, 1, 3), top, left)
+	})
+
+	t.Run("Interleaved views", func(t *testing.T) {
+		buffer := matrix.NewFromSlice([]float64{0, 0, 1, 2, 0, 0, 3, 4}, 2, 4)
+		matrix.MulInto(buffer.Slice(0, 2, 0, 2), matrix.Identity[float64](2), buffer.Slice(0, 2, 2, 4))
+		expected := "1 2 1 2\n3 4 3 4"
+		if buffer.String() != expected {
+			t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, buffer)
+		}
+	})
+
+	t.Run("Clone does not share data", func(t *testing.T) {
+		clone := top.Clone()
+		clone.Set(0, 0, 100)
+		if m.Get(0, 0) == 100 {
+			t.Fatalf("expected a change to a clone not to be visible in the matrix")
+		}
+	})
+}
+
+func TestAliasedOperands(t *testing.T) {
+	tests := []struct {
+		name     string
+		apply    func(m *matrix.Matrix)
+		expected string
+	}{
+		{
+			"AddTo transpose",
+			func(m *matrix.Matrix) { matrix.AddTo(m, m, m.Transpose()) },
+			"2 5\n5 8",
+		},
+		{
+			"SubTo transpose first",
+			func(m *matrix.Matrix) { matrix.SubTo(m, m.Transpose(), m) },
+			"0 1\n-1 0",
+		},
+		{
+			"HadamardTo broadcast row",
+			func(m *matrix.Matrix) { matrix.HadamardTo(m, m, m.RowView(1)) },
+			"3 8\n9 16",
+		},
+		{
+			"DivTo broadcast col",
+			func(m *matrix.Matrix) { matrix.DivTo(m, m, m.ColView(0)) },
+			"1 2\n1 1.3333333333333333",
+		},
+		{
+			"ZipTo same view",
+			func(m *matrix.Matrix) {
+				matrix.ZipTo(m, m, m, func(x, y float64) float64 { return x * y })
+			},
+			"1 4\n9 16",
+		},
+		{
+			"Copy transpose",
+			func(m *matrix.Matrix) { matrix.Copy(m, m.Transpose()) },
+			"1 3\n2 4",
+		},
+		{
+			"MapTo transpose",
+			func(m *matrix.Matrix) { matrix.MapTo(m, m.Transpose(), func(x float64) float64 { return -x }) },
+			"-1 -3\n-2 -4",
+		},
+		{
+			"ScaleTo transpose",
+			func(m *matrix.Matrix) { matrix.ScaleTo(m, m.Transpose(), 2) },
+			"2 6\n4 8",
+		},
+		{
+			"AddScaled transpose",
+			func(m *matrix.Matrix) { matrix.AddScaled(m, 10, m.Transpose()) },
+			"11 32\n23 44",
+		},
+		{
+			"Overlapping views",
+			func(m *matrix.Matrix) {
+				col := m.ColView(1).Transpose()
+				matrix.AddTo(col, m.ColView(1).Transpose(), m.RowView(0))
+			},
+			"1 3\n3 6",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := matrix.NewFromSlice([]float64{1, 2, 3, 4}, 2, 2)
+			test.apply(m)
+			if m.String() != test.expected {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", test.expected, m)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math/rand"
 )
 
 // errorString represents an error in reading mnist data
@@ -28,6 +29,19 @@ type Set struct {
 	Images []Image
 }
 
+// Shuffle randomly reorders the set, keeping each label with its image. The
+// order depends only on the numbers drawn from rng, so a generator with a
+// fixed seed always shuffles the same set the same way.
+func (s *Set) Shuffle(rng *rand.Rand) {
+	if len(s.Labels) != len(s.Images) {
+		panic(fmt.Errorf("mnist: cannot shuffle %d labels with %d images", len(s.Labels), len(s.Images)))
+	}
+	rng.Shuffle(len(s.Labels), func(i, j int) {
+		s.Labels[i], s.Labels[j] = s.Labels[j], s.Labels[i]
+		s.Images[i], s.Images[j] = s.Images[j], s.Images[i]
+	})
+}
+
 // Image is an MNIST image
 type Image struct {
 	Rows   int32
@@ -3,6 +3,7 @@ package mnist
 import (
 	"bytes"
 	"encoding/binary"
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -132,3 +133,30 @@ func TestImageGet(t *testing.T) {
 		})
 	}
 }
+
+func TestShuffle(t *testing.T) {
+	newSet := func() *Set {
+		set := &Set{Labels: digits()}
+		for _, label := range set.Labels {
+			set.Images = append(set.Images, Image{1, 1, []byte{label}})
+		}
+		return set
+	}
+
+	first := newSet()
+	first.Shuffle(rand.New(rand.NewSource(1)))
+	if reflect.DeepEqual(first.Labels, digits()) {
+		t.Fatalf("Expected the labels to be reordered but got %v", first.Labels)
+	}
+	for i, label := range first.Labels {
+		if first.Images[i].Pixels[0] != label {
+			t.Fatalf("Expected image %d to stay with label %d but got image %v", i, label, first.Images[i])
+		}
+	}
+
+	second := newSet()
+	second.Shuffle(rand.New(rand.NewSource(1)))
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("Expected the same seed to shuffle the same way but got %v and %v", first.Labels, second.Labels)
+	}
+}
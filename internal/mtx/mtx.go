@@ -0,0 +1,489 @@
+// Package mtx reads and writes matrices in the Matrix Market exchange
+// format (.mtx), which is understood by most numerical tools, including
+// MATLAB, SciPy and Julia.
+//
+// Both of the format's layouts are supported: coordinate, which lists the
+// nonzero entries of a sparse matrix, and array, which lists every entry of
+// a dense one in column-major order. Entries may be real, integer or
+// pattern (coordinate only, where every listed entry is 1), and the matrix
+// may be general, symmetric or skew-symmetric. Complex and Hermitian
+// matrices are not supported.
+//
+// Files are read as a stream of entries, so a large sparse matrix never
+// needs to be held in memory as a dense one, and the size declared in the
+// header is checked against what the file actually contains. Since a header
+// can declare a huge matrix in a few bytes, ReadDense and ReadCSR reject
+// matrices larger than MaxDenseSize and MaxSparseRows before allocating
+// them.
+package mtx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+// errorString represents an error in reading or writing a Matrix Market file
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrInvalidHeader specifies that the banner or size line of a file is
+	// missing or malformed.
+	ErrInvalidHeader errorString = "mtx: invalid header"
+	// ErrUnsupported specifies that a file uses a part of the format that
+	// isn't supported, such as complex entries.
+	ErrUnsupported errorString = "mtx: unsupported format"
+	// ErrInvalidEntry specifies that an entry is malformed or lies outside
+	// of the declared dimensions or symmetry.
+	ErrInvalidEntry errorString = "mtx: invalid entry"
+	// ErrEntryCount specifies that a file has more or fewer entries than its
+	// header declares.
+	ErrEntryCount errorString = "mtx: the number of entries doesn't match the header"
+)
+
+const (
+	unexpectedReadErr  = "mtx: unexpected error while reading: %w"
+	unexpectedWriteErr = "mtx: unexpected error while writing: %w"
+)
+
+// Layout is how the entries of a matrix are listed.
+type Layout string
+
+const (
+	// Coordinate lists the row, col and value of each nonzero entry.
+	Coordinate Layout = "coordinate"
+	// Array lists the value of every entry, in column-major order.
+	Array Layout = "array"
+)
+
+// Field is the type of the entries.
+type Field string
+
+const (
+	// Real entries are floating point numbers.
+	Real Field = "real"
+	// Integer entries are integers.
+	Integer Field = "integer"
+	// Pattern entries have no value and are all 1. Only coordinate files
+	// can have them.
+	Pattern Field = "pattern"
+)
+
+// Symmetry is the structure of the matrix that lets a file leave out
+// entries.
+type Symmetry string
+
+const (
+	// General matrices list every entry.
+	General Symmetry = "general"
+	// Symmetric matrices only list the entries on and below the diagonal;
+	// the entry at (i, j) is the same as the one at (j, i).
+	Symmetric Symmetry = "symmetric"
+	// SkewSymmetric matrices only list the entries below the diagonal; the
+	// entry at (i, j) is the negative of the one at (j, i), and the
+	// diagonal is 0.
+	SkewSymmetric Symmetry = "skew-symmetric"
+)
+
+// Header describes the matrix in a Matrix Market file.
+type Header struct {
+	Layout   Layout
+	Field    Field
+	Symmetry Symmetry
+	// Rows and Cols are the dimensions of the matrix.
+	Rows, Cols int
+	// Entries is the number of entries listed in the file. For a symmetric
+	// matrix this is fewer than the number of entries it has.
+	Entries int
+}
+
+const banner = "%%MatrixMarket"
+
+// Reader reads the entries of a Matrix Market file one at a time.
+type Reader struct {
+	scanner *bufio.Scanner
+	header  Header
+	line    int
+	// read is the number of entries read from the file so far.
+	read int
+	// mirror holds the entry on the other side of the diagonal from the
+	// last one read from a symmetric file, which is returned next.
+	mirror    *entry
+	lastError error
+}
+
+type entry struct {
+	row, col int
+	value    float64
+}
+
+// NewReader returns a reader of a Matrix Market file, after reading its
+// header. The error wraps ErrInvalidHeader or ErrUnsupported if the header
+// is malformed or describes a matrix that can't be read.
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{scanner: bufio.NewScanner(r)}
+	if err := reader.readHeader(); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// nextLine returns the next line that isn't a comment or blank, or io.EOF
+// if there are none.
+func (r *Reader) nextLine() (string, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "%") {
+			return line, nil
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return "", fmt.Errorf(unexpectedReadErr, err)
+	}
+	return "", io.EOF
+}
+
+func (r *Reader) readHeader() error {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return fmt.Errorf(unexpectedReadErr, err)
+		}
+		return fmt.Errorf("%w: the file is empty", ErrInvalidHeader)
+	}
+	r.line++
+	fields := strings.Fields(strings.ToLower(r.scanner.Text()))
+	if len(fields) != 5 || fields[0] != strings.ToLower(banner) || fields[1] != "matrix" {
+		return fmt.Errorf("%w: expected a banner like %q, instead found %q", ErrInvalidHeader, banner+" matrix coordinate real general", r.scanner.Text())
+	}
+	h := Header{Layout: Layout(fields[2]), Field: Field(fields[3]), Symmetry: Symmetry(fields[4])}
+	if h.Layout != Coordinate && h.Layout != Array {
+		return fmt.Errorf("%w: layout %q", ErrUnsupported, h.Layout)
+	}
+	if h.Field != Real && h.Field != Integer && (h.Field != Pattern || h.Layout != Coordinate) {
+		return fmt.Errorf("%w: %s entries in the %s layout", ErrUnsupported, h.Field, h.Layout)
+	}
+	if h.Symmetry != General && h.Symmetry != Symmetric && h.Symmetry != SkewSymmetric {
+		return fmt.Errorf("%w: symmetry %q", ErrUnsupported, h.Symmetry)
+	}
+
+	line, err := r.nextLine()
+	if err == io.EOF {
+		return fmt.Errorf("%w: the size line is missing", ErrInvalidHeader)
+	}
+	if err != nil {
+		return err
+	}
+	sizes := strings.Fields(line)
+	want := 3
+	if h.Layout == Array {
+		want = 2
+	}
+	values := make([]int, len(sizes))
+	for i, size := range sizes {
+		if values[i], err = strconv.Atoi(size); err != nil {
+			break
+		}
+	}
+	if len(sizes) != want || err != nil {
+		return fmt.Errorf("%w: line %d: expected %d sizes, instead found %q", ErrInvalidHeader, r.line, want, line)
+	}
+	h.Rows, h.Cols = values[0], values[1]
+	if h.Rows <= 0 || h.Cols <= 0 {
+		return fmt.Errorf("%w: line %d: the matrix is %dx%d", ErrInvalidHeader, r.line, h.Rows, h.Cols)
+	}
+	// Leave room for the entries of a symmetric matrix, n * (n+1) / 2.
+	if h.Cols >= math.MaxInt/h.Rows {
+		return fmt.Errorf("%w: line %d: the matrix is too large (%dx%d)", ErrInvalidHeader, r.line, h.Rows, h.Cols)
+	}
+	if h.Symmetry != General && h.Rows != h.Cols {
+		return fmt.Errorf("%w: line %d: a %s matrix must be square, instead it is %dx%d", ErrInvalidHeader, r.line, h.Symmetry, h.Rows, h.Cols)
+	}
+	switch {
+	case h.Layout == Coordinate:
+		h.Entries = values[2]
+	case h.Symmetry == Symmetric:
+		h.Entries = h.Rows * (h.Rows + 1) / 2
+	case h.Symmetry == SkewSymmetric:
+		h.Entries = h.Rows * (h.Rows - 1) / 2
+	default:
+		h.Entries = h.Rows * h.Cols
+	}
+	if h.Entries < 0 {
+		return fmt.Errorf("%w: line %d: %d entries", ErrInvalidHeader, r.line, h.Entries)
+	}
+	r.header = h
+	return nil
+}
+
+// Header returns the header of the file.
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// arrayPosition returns the row and col of the nth entry of an array file.
+func (r *Reader) arrayPosition(n int) (row, col int) {
+	h := r.header
+	if h.Symmetry == General {
+		return n % h.Rows, n / h.Rows
+	}
+	// Only the lower triangle is listed, a column at a time, starting on
+	// the diagonal, or just below it for a skew-symmetric matrix.
+	start := 0
+	if h.Symmetry == SkewSymmetric {
+		start = 1
+	}
+	for col = 0; ; col++ {
+		length := h.Rows - col - start
+		if n < length {
+			return col + start + n, col
+		}
+		n -= length
+	}
+}
+
+// Next returns the next entry of the matrix, with its row and col counted
+// from 0. It returns io.EOF once every entry has been read.
+//
+// A coordinate file's entries are returned in the order they are listed,
+// and an array file's in column-major order, including any zeros. The
+// entries a symmetric or skew-symmetric file leaves out are returned too,
+// each straight after the one it mirrors.
+//
+// The error wraps ErrInvalidEntry if an entry is malformed or outside of
+// the matrix, or ErrEntryCount if the file has more or fewer entries than
+// its header declares. After an error, Next keeps returning it.
+func (r *Reader) Next() (row, col int, value float64, err error) {
+	if r.lastError != nil {
+		return 0, 0, 0, r.lastError
+	}
+	row, col, value, err = r.next()
+	if err != nil {
+		r.lastError = err
+	}
+	return row, col, value, err
+}
+
+func (r *Reader) next() (row, col int, value float64, err error) {
+	if r.mirror != nil {
+		e := r.mirror
+		r.mirror = nil
+		return e.row, e.col, e.value, nil
+	}
+	h := r.header
+
+	line, err := r.nextLine()
+	if err == io.EOF {
+		if r.read != h.Entries {
+			return 0, 0, 0, fmt.Errorf("%w: the header declares %d entries, but the file has %d", ErrEntryCount, h.Entries, r.read)
+		}
+		return 0, 0, 0, io.EOF
+	}
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if r.read == h.Entries {
+		return 0, 0, 0, fmt.Errorf("%w: line %d: the header declares %d entries, but there are more", ErrEntryCount, r.line, h.Entries)
+	}
+
+	fields := strings.Fields(line)
+	want := 1
+	if h.Layout == Coordinate {
+		want = 3
+		if h.Field == Pattern {
+			want = 2
+		}
+	}
+	if len(fields) != want {
+		return 0, 0, 0, fmt.Errorf("%w: line %d: expected %d fields, instead found %q", ErrInvalidEntry, r.line, want, line)
+	}
+
+	if h.Layout == Coordinate {
+		row, err = strconv.Atoi(fields[0])
+		if err == nil {
+			col, err = strconv.Atoi(fields[1])
+		}
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("%w: line %d: invalid index in %q", ErrInvalidEntry, r.line, line)
+		}
+		// Indices in the file count from 1.
+		row, col = row-1, col-1
+		if row < 0 || col < 0 || row >= h.Rows || col >= h.Cols {
+			return 0, 0, 0, fmt.Errorf("%w: line %d: (%d, %d) is outside of a %dx%d matrix", ErrInvalidEntry, r.line, row+1, col+1, h.Rows, h.Cols)
+		}
+		if h.Symmetry == Symmetric && row < col || h.Symmetry == SkewSymmetric && row <= col {
+			return 0, 0, 0, fmt.Errorf("%w: line %d: (%d, %d) is not below the diagonal of a %s matrix", ErrInvalidEntry, r.line, row+1, col+1, h.Symmetry)
+		}
+	} else {
+		row, col = r.arrayPosition(r.read)
+	}
+
+	value = 1
+	if h.Field != Pattern {
+		if value, err = parseValue(fields[len(fields)-1], h.Field); err != nil {
+			return 0, 0, 0, fmt.Errorf("%w: line %d: %v", ErrInvalidEntry, r.line, err)
+		}
+	}
+	r.read++
+
+	switch {
+	case h.Symmetry == Symmetric && row != col:
+		r.mirror = &entry{row: col, col: row, value: value}
+	case h.Symmetry == SkewSymmetric:
+		r.mirror = &entry{row: col, col: row, value: -value}
+	}
+	return row, col, value, nil
+}
+
+func parseValue(field string, f Field) (float64, error) {
+	if f == Integer {
+		v, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not an integer", field)
+		}
+		return float64(v), nil
+	}
+	v, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", field)
+	}
+	return v, nil
+}
+
+// ReadDense reads a Matrix Market file of either layout into a matrix,
+// converting its entries to T. Entries listed more than once are summed.
+// The error wraps ErrInvalidHeader if the matrix has more than MaxDenseSize
+// entries; see Reader.Next for the other errors it returns.
+func ReadDense[T matrix.Float](r io.Reader) (*matrix.Dense[T], error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	h := reader.Header()
+	if h.Rows*h.Cols > MaxDenseSize {
+		return nil, fmt.Errorf("%w: a %dx%d matrix has more than the %d entries ReadDense allows", ErrInvalidHeader, h.Rows, h.Cols, MaxDenseSize)
+	}
+	result := matrix.NewDense[T](h.Rows, h.Cols)
+	for {
+		row, col, value, err := reader.Next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Set(row, col, result.Get(row, col)+T(value))
+	}
+}
+
+// Read reads a Matrix Market file into a float64 matrix. See ReadDense.
+func Read(r io.Reader) (*matrix.Matrix, error) {
+	return ReadDense[float64](r)
+}
+
+const (
+	// MaxDenseSize is the most entries ReadDense allocates for a matrix,
+	// which is 1 GiB of float64s.
+	MaxDenseSize = 1 << 27
+	// MaxSparseRows is the most rows ReadCSR allocates for a matrix, since
+	// a CSR matrix stores an offset for every row, however few entries it
+	// has.
+	MaxSparseRows = 1 << 26
+	// maxPreallocated is the most entries ReadCSR makes room for before
+	// reading them.
+	maxPreallocated = 1 << 16
+)
+
+// ReadCSR reads a Matrix Market file of either layout into a sparse matrix,
+// converting its entries to T, without ever holding it as a dense one.
+// Entries listed more than once are summed, and zeros in an array file are
+// not stored. The error wraps ErrInvalidHeader if the matrix has more than
+// MaxSparseRows rows; see Reader.Next for the other errors it returns.
+func ReadCSR[T matrix.Float](r io.Reader) (*matrix.CSR[T], error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	h := reader.Header()
+	if h.Rows > MaxSparseRows {
+		return nil, fmt.Errorf("%w: the matrix has %d rows, more than the %d ReadCSR allows", ErrInvalidHeader, h.Rows, MaxSparseRows)
+	}
+	var rows, cols []int
+	var values []T
+	if h.Layout == Coordinate {
+		capacity := h.Entries
+		if h.Symmetry != General {
+			capacity *= 2
+		}
+		// The header may declare far more entries than the file holds.
+		if capacity > maxPreallocated {
+			capacity = maxPreallocated
+		}
+		rows, cols, values = make([]int, 0, capacity), make([]int, 0, capacity), make([]T, 0, capacity)
+	}
+	for {
+		row, col, value, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if value != 0 {
+			rows, cols, values = append(rows, row), append(cols, col), append(values, T(value))
+		}
+	}
+	return matrix.NewCSR(h.Rows, h.Cols, rows, cols, values), nil
+}
+
+func formatValue[T matrix.Float](value T) string {
+	bitSize := 64
+	if _, ok := any(value).(float32); ok {
+		bitSize = 32
+	}
+	return strconv.FormatFloat(float64(value), 'g', -1, bitSize)
+}
+
+// Write writes a matrix as a general, real Matrix Market file in the array
+// layout. It is written a line at a time, so it is never held in memory as
+// text.
+func Write[T matrix.Float](w io.Writer, m *matrix.Dense[T]) error {
+	rows, cols := m.Dimensions()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s matrix %s %s %s\n%d %d\n", banner, Array, Real, General, rows, cols)
+	for c := 0; c < cols; c++ {
+		for r := 0; r < rows; r++ {
+			bw.WriteString(formatValue(m.Get(r, c)))
+			bw.WriteByte('\n')
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf(unexpectedWriteErr, err)
+	}
+	return nil
+}
+
+// WriteSparse writes a sparse matrix as a general, real Matrix Market file
+// in the coordinate layout, listing each stored entry in the order that Do
+// visits them.
+func WriteSparse[T matrix.Float](w io.Writer, s matrix.Sparse[T]) error {
+	rows, cols := s.Dimensions()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s matrix %s %s %s\n%d %d %d\n", banner, Coordinate, Real, General, rows, cols, s.NNZ())
+	s.Do(func(row, col int, value T) {
+		fmt.Fprintf(bw, "%d %d %s\n", row+1, col+1, formatValue(value))
+	})
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf(unexpectedWriteErr, err)
+	}
+	return nil
+}
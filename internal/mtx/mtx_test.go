@@ -0,0 +1,215 @@
+package mtx_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/mtx"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		expected string
+	}{
+		{
+			"Coordinate",
+			"%%MatrixMarket matrix coordinate real general\n% a comment\n\n2 3 3\n1 1 1.5\n2 3 -2\n1 2 4e-1\n",
+			"1.5 0.4 0\n0 0 -2",
+		},
+		{
+			"Coordinate duplicates",
+			"%%MatrixMarket matrix coordinate real general\n2 2 3\n1 1 1\n1 1 2\n2 2 5\n",
+			"3 0\n0 5",
+		},
+		{
+			"Integer",
+			"%%MatrixMarket matrix coordinate integer general\n2 2 2\n1 2 7\n2 1 -3\n",
+			"0 7\n-3 0",
+		},
+		{
+			"Pattern",
+			"%%MatrixMarket matrix coordinate pattern general\n2 2 2\n1 1\n2 1\n",
+			"1 0\n1 0",
+		},
+		{
+			"Symmetric coordinate",
+			"%%MatrixMarket matrix coordinate real symmetric\n3 3 3\n1 1 1\n3 1 2\n2 2 3\n",
+			"1 0 2\n0 3 0\n2 0 0",
+		},
+		{
+			"Skew-symmetric coordinate",
+			"%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 1\n2 1 4\n",
+			"0 -4\n4 0",
+		},
+		{
+			"Array",
+			"%%MatrixMarket matrix array real general\n% column-major\n2 3\n1\n4\n2\n5\n3\n6\n",
+			"1 2 3\n4 5 6",
+		},
+		{
+			"Symmetric array",
+			"%%MATRIXMARKET Matrix Array Real Symmetric\n3 3\n1\n2\n3\n4\n5\n6\n",
+			"1 2 3\n2 4 5\n3 5 6",
+		},
+		{
+			"Skew-symmetric array",
+			"%%MatrixMarket matrix array integer skew-symmetric\n3 3\n1\n2\n3\n",
+			"0 -1 -2\n1 0 -3\n2 3 0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := matrix.Parse(test.expected)
+			result, err := mtx.Read(strings.NewReader(test.file))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !matrix.Equal(result, expected) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+			}
+			sparse, err := mtx.ReadCSR[float32](strings.NewReader(test.file))
+			if err != nil {
+				t.Fatalf("unexpected error reading a CSR matrix: %v", err)
+			}
+			if expected := matrix.Convert[float32](expected); !matrix.Equal(sparse.ToDense(), expected) {
+				t.Fatalf("expected the CSR matrix to be:\n\n%s\n\ninstead got:\n\n%s", expected, sparse)
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	file := "%%MatrixMarket matrix coordinate real symmetric\n3 3 2\n2 1 5\n3 3 1\n"
+	reader, err := mtx.NewReader(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedHeader := mtx.Header{Layout: mtx.Coordinate, Field: mtx.Real, Symmetry: mtx.Symmetric, Rows: 3, Cols: 3, Entries: 2}
+	if h := reader.Header(); h != expectedHeader {
+		t.Fatalf("expected the header %+v, instead got %+v", expectedHeader, h)
+	}
+	type entry struct {
+		row, col int
+		value    float64
+	}
+	var entries []entry
+	for {
+		row, col, value, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		entries = append(entries, entry{row, col, value})
+	}
+	expected := []entry{{1, 0, 5}, {0, 1, 5}, {2, 2, 1}}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected the entries %v, instead got %v", expected, entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Fatalf("expected the entries %v, instead got %v", expected, entries)
+		}
+	}
+	if _, _, _, err := reader.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF after the last entry, instead got %v", err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want error
+	}{
+		{"Empty", "", mtx.ErrInvalidHeader},
+		{"No banner", "2 2 0\n", mtx.ErrInvalidHeader},
+		{"No size", "%%MatrixMarket matrix coordinate real general\n% just a comment\n", mtx.ErrInvalidHeader},
+		{"Bad size", "%%MatrixMarket matrix coordinate real general\n2 2\n", mtx.ErrInvalidHeader},
+		{"Zero rows", "%%MatrixMarket matrix array real general\n0 2\n", mtx.ErrInvalidHeader},
+		{"Symmetric not square", "%%MatrixMarket matrix coordinate real symmetric\n2 3 0\n", mtx.ErrInvalidHeader},
+		{"Overflowing size", "%%MatrixMarket matrix coordinate real general\n3037000500 3037000500 1\n1 1 1\n", mtx.ErrInvalidHeader},
+		{"Huge entry count", "%%MatrixMarket matrix coordinate real general\n2 2 100000000000000000\n1 1 1\n", mtx.ErrEntryCount},
+		{"Complex", "%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 0\n", mtx.ErrUnsupported},
+		{"Hermitian", "%%MatrixMarket matrix coordinate real hermitian\n1 1 0\n", mtx.ErrUnsupported},
+		{"Pattern array", "%%MatrixMarket matrix array pattern general\n1 1\n", mtx.ErrUnsupported},
+		{"Too few entries", "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n", mtx.ErrEntryCount},
+		{"Too many entries", "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1 1\n2 2 1\n", mtx.ErrEntryCount},
+		{"Too few array entries", "%%MatrixMarket matrix array real general\n2 2\n1\n2\n3\n", mtx.ErrEntryCount},
+		{"Out of range", "%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n", mtx.ErrInvalidEntry},
+		{"Zero index", "%%MatrixMarket matrix coordinate real general\n2 2 1\n0 1 1\n", mtx.ErrInvalidEntry},
+		{"Above the diagonal", "%%MatrixMarket matrix coordinate real symmetric\n2 2 1\n1 2 1\n", mtx.ErrInvalidEntry},
+		{"Skew diagonal", "%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 1\n1 1 1\n", mtx.ErrInvalidEntry},
+		{"Not a number", "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1 x\n", mtx.ErrInvalidEntry},
+		{"Not an integer", "%%MatrixMarket matrix coordinate integer general\n2 2 1\n1 1 1.5\n", mtx.ErrInvalidEntry},
+		{"Missing value", "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1\n", mtx.ErrInvalidEntry},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := mtx.Read(strings.NewReader(test.file)); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+			if _, err := mtx.ReadCSR[float64](strings.NewReader(test.file)); !errors.Is(err, test.want) {
+				t.Fatalf("expected ReadCSR to return an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestReadLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		wantDense error
+		wantCSR   error
+	}{
+		{"Huge sparse", "%%MatrixMarket matrix coordinate real general\n1000000000 1000000000 0\n", mtx.ErrInvalidHeader, mtx.ErrInvalidHeader},
+		{"Huge array", "%%MatrixMarket matrix array real general\n3000000000 3000000000\n", mtx.ErrInvalidHeader, mtx.ErrInvalidHeader},
+		{"Huge column", "%%MatrixMarket matrix coordinate real general\n100000000000000 1 0\n", mtx.ErrInvalidHeader, mtx.ErrInvalidHeader},
+		{"Too large to be dense", "%%MatrixMarket matrix coordinate real general\n100000 100000 1\n1 1 1\n", mtx.ErrInvalidHeader, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := mtx.ReadDense[float64](strings.NewReader(test.file)); !errors.Is(err, test.wantDense) {
+				t.Fatalf("expected ReadDense to return an error wrapping %v, instead got %v", test.wantDense, err)
+			}
+			if _, err := mtx.ReadCSR[float64](strings.NewReader(test.file)); !errors.Is(err, test.wantCSR) {
+				t.Fatalf("expected ReadCSR to return an error wrapping %v, instead got %v", test.wantCSR, err)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	m := matrix.Parse("1 0 -2.5\n0 1e-300 0")
+	var buffer bytes.Buffer
+	if err := mtx.Write(&buffer, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "%%MatrixMarket matrix array real general\n2 3\n1\n0\n0\n1e-300\n-2.5\n0\n"
+	if buffer.String() != expected {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, buffer.String())
+	}
+	if result, err := mtx.Read(&buffer); err != nil || !matrix.Equal(result, m) {
+		t.Fatalf("expected to read back:\n\n%s\n\ninstead got:\n\n%v (%v)", m, result, err)
+	}
+
+	buffer.Reset()
+	if err := mtx.WriteSparse[float64](&buffer, matrix.ToCSR(m)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = "%%MatrixMarket matrix coordinate real general\n2 3 3\n1 1 1\n1 3 -2.5\n2 2 1e-300\n"
+	if buffer.String() != expected {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, buffer.String())
+	}
+	if result, err := mtx.Read(&buffer); err != nil || !matrix.Equal(result, m) {
+		t.Fatalf("expected to read back:\n\n%s\n\ninstead got:\n\n%v (%v)", m, result, err)
+	}
+}
@@ -0,0 +1,143 @@
+package neural
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+// Initializer returns the initial weights of a layer with fanIn inputs and
+// fanOut outputs, as a fanOut x fanIn matrix. All of its random numbers must
+// be drawn from rng.
+type Initializer func(rng *rand.Rand, fanIn, fanOut int) *matrix.Matrix
+
+// fill returns an Initializer that sets every weight to a value returned by
+// sample, which may depend on the fan-in and fan-out of the layer.
+func fill(sample func(rng *rand.Rand, fanIn, fanOut int) float64) Initializer {
+	return func(rng *rand.Rand, fanIn, fanOut int) *matrix.Matrix {
+		data := make([]float64, fanOut*fanIn)
+		for i := range data {
+			data[i] = sample(rng, fanIn, fanOut)
+		}
+		return matrix.NewFromSlice(data, fanOut, fanIn)
+	}
+}
+
+// uniform returns a value sampled uniformly from [-limit, limit).
+func uniform(rng *rand.Rand, limit float64) float64 {
+	return (2*rng.Float64() - 1) * limit
+}
+
+// StandardNormal initializes every weight from the standard normal
+// distribution, N(0, 1). It is the default Initializer, but its variance
+// doesn't shrink as layers get wider, so it tends to saturate sigmoids in
+// large networks.
+func StandardNormal() Initializer {
+	return func(rng *rand.Rand, fanIn, fanOut int) *matrix.Matrix {
+		return matrix.NewRandomNormalFrom(rng, fanOut, fanIn)
+	}
+}
+
+// XavierUniform initializes weights uniformly from [-limit, limit), where
+// limit is sqrt(6 / (fanIn + fanOut)). Also known as Glorot uniform, it suits
+// sigmoid and tanh activations.
+func XavierUniform() Initializer {
+	return fill(func(rng *rand.Rand, fanIn, fanOut int) float64 {
+		return uniform(rng, math.Sqrt(6/float64(fanIn+fanOut)))
+	})
+}
+
+// XavierNormal initializes weights from a normal distribution with a mean of
+// 0 and a standard deviation of sqrt(2 / (fanIn + fanOut)). Also known as
+// Glorot normal, it suits sigmoid and tanh activations.
+func XavierNormal() Initializer {
+	return fill(func(rng *rand.Rand, fanIn, fanOut int) float64 {
+		return rng.NormFloat64() * math.Sqrt(2/float64(fanIn+fanOut))
+	})
+}
+
+// HeUniform initializes weights uniformly from [-limit, limit), where limit
+// is sqrt(6 / fanIn). Also known as Kaiming uniform, it suits ReLU
+// activations.
+func HeUniform() Initializer {
+	return fill(func(rng *rand.Rand, fanIn, fanOut int) float64 {
+		return uniform(rng, math.Sqrt(6/float64(fanIn)))
+	})
+}
+
+// HeNormal initializes weights from a normal distribution with a mean of 0
+// and a standard deviation of sqrt(2 / fanIn). Also known as Kaiming normal,
+// it suits ReLU activations.
+func HeNormal() Initializer {
+	return fill(func(rng *rand.Rand, fanIn, fanOut int) float64 {
+		return rng.NormFloat64() * math.Sqrt(2/float64(fanIn))
+	})
+}
+
+// LeCunUniform initializes weights uniformly from [-limit, limit), where
+// limit is sqrt(3 / fanIn).
+func LeCunUniform() Initializer {
+	return fill(func(rng *rand.Rand, fanIn, fanOut int) float64 {
+		return uniform(rng, math.Sqrt(3/float64(fanIn)))
+	})
+}
+
+// LeCunNormal initializes weights from a normal distribution with a mean of
+// 0 and a standard deviation of sqrt(1 / fanIn).
+func LeCunNormal() Initializer {
+	return fill(func(rng *rand.Rand, fanIn, fanOut int) float64 {
+		return rng.NormFloat64() * math.Sqrt(1/float64(fanIn))
+	})
+}
+
+// TruncatedNormal initializes weights from a normal distribution with the
+// given mean and standard deviation, redrawing any value that is more than
+// two standard deviations from the mean.
+func TruncatedNormal(mean, stddev float64) Initializer {
+	return fill(func(rng *rand.Rand, fanIn, fanOut int) float64 {
+		for {
+			v := rng.NormFloat64()
+			if math.Abs(v) <= 2 {
+				return mean + v*stddev
+			}
+		}
+	})
+}
+
+// Uniform initializes weights uniformly from [low, high).
+func Uniform(low, high float64) Initializer {
+	return func(rng *rand.Rand, fanIn, fanOut int) *matrix.Matrix {
+		return matrix.NewRandomUniformFrom(rng, fanOut, fanIn, low, high)
+	}
+}
+
+// Constant initializes every weight to the given value.
+func Constant(value float64) Initializer {
+	return func(rng *rand.Rand, fanIn, fanOut int) *matrix.Matrix {
+		return matrix.Fill(fanOut, fanIn, value)
+	}
+}
+
+// Orthogonal initializes weights to a random orthogonal matrix multiplied by
+// gain: the rows of the weights are orthonormal if there are fewer outputs
+// than inputs, and the columns are otherwise. This keeps the length of
+// signals from growing or shrinking as they pass through deep networks.
+//
+// The orthogonal matrix is the one closest to a matrix of standard normal
+// values, found with its singular value decomposition, as in Saxe et al.
+// (2014).
+func Orthogonal(gain float64) Initializer {
+	return func(rng *rand.Rand, fanIn, fanOut int) *matrix.Matrix {
+		svd, err := matrix.NewSVD(StandardNormal()(rng, fanIn, fanOut))
+		if err != nil {
+			panic(fmt.Errorf("neural: the orthogonal initializer failed: %w", err))
+		}
+		// The random matrix is U * diag(values) * Vᵀ, and the orthogonal
+		// matrix closest to it is U * Vᵀ, which is fanOut x fanIn.
+		q := matrix.Multiply(svd.U(), svd.V().Transpose())
+		matrix.ScaleInPlace(q, gain)
+		return q
+	}
+}
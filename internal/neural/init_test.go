@@ -0,0 +1,156 @@
+package neural_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/neural"
+)
+
+func TestInitializerDistributions(t *testing.T) {
+	fanIn, fanOut := 784, 100
+	tests := []struct {
+		name         string
+		initializer  neural.Initializer
+		mean, stddev float64
+		limit        float64 // 0 if the values are unbounded
+	}{
+		{"StandardNormal", neural.StandardNormal(), 0, 1, 0},
+		{"XavierUniform", neural.XavierUniform(), 0, math.Sqrt(2.0 / 884), math.Sqrt(6.0 / 884)},
+		{"XavierNormal", neural.XavierNormal(), 0, math.Sqrt(2.0 / 884), 0},
+		{"HeUniform", neural.HeUniform(), 0, math.Sqrt(2.0 / 784), math.Sqrt(6.0 / 784)},
+		{"HeNormal", neural.HeNormal(), 0, math.Sqrt(2.0 / 784), 0},
+		{"LeCunUniform", neural.LeCunUniform(), 0, math.Sqrt(1.0 / 784), math.Sqrt(3.0 / 784)},
+		{"LeCunNormal", neural.LeCunNormal(), 0, math.Sqrt(1.0 / 784), 0},
+		// A normal distribution truncated at two standard deviations has a
+		// standard deviation about 0.88 times the original.
+		{"TruncatedNormal", neural.TruncatedNormal(1, 0.5), 1, 0.5 * 0.8796, 0},
+		{"Uniform", neural.Uniform(2, 4), 3, 2 / math.Sqrt(12), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			weights := test.initializer(rng, fanIn, fanOut)
+			rows, cols := weights.Dimensions()
+			if rows != fanOut || cols != fanIn {
+				t.Fatalf("expected a %dx%d matrix, instead got a %dx%d matrix", fanOut, fanIn, rows, cols)
+			}
+
+			mean := matrix.Mean(weights)
+			stddev := math.Sqrt(matrix.Variance(weights))
+			if math.Abs(mean-test.mean) > 0.05*test.stddev {
+				t.Fatalf("expected a mean of %f, instead it was %f", test.mean, mean)
+			}
+			if math.Abs(stddev-test.stddev) > 0.02*test.stddev {
+				t.Fatalf("expected a standard deviation of %f, instead it was %f", test.stddev, stddev)
+			}
+			if test.limit != 0 {
+				if largest := matrix.Max(weights); largest >= test.limit {
+					t.Fatalf("expected every weight to be less than %f, instead found %f", test.limit, largest)
+				}
+				if smallest := matrix.Min(weights); smallest < -test.limit {
+					t.Fatalf("expected every weight to be at least %f, instead found %f", -test.limit, smallest)
+				}
+			}
+		})
+	}
+}
+
+func TestTruncatedNormalBounds(t *testing.T) {
+	weights := neural.TruncatedNormal(1, 0.5)(rand.New(rand.NewSource(1)), 100, 100)
+	if largest := matrix.Max(weights); largest > 2 {
+		t.Fatalf("expected every weight to be at most 2, instead found %f", largest)
+	}
+	if smallest := matrix.Min(weights); smallest < 0 {
+		t.Fatalf("expected every weight to be at least 0, instead found %f", smallest)
+	}
+}
+
+func TestConstant(t *testing.T) {
+	weights := neural.Constant(0.25)(rand.New(rand.NewSource(1)), 3, 2)
+	expected := "0.25 0.25 0.25\n0.25 0.25 0.25"
+	if weights.String() != expected {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, weights)
+	}
+}
+
+func TestOrthogonal(t *testing.T) {
+	tests := []struct {
+		name          string
+		fanIn, fanOut int
+	}{
+		{"Square", 50, 50},
+		{"Wide", 784, 30},
+		{"Tall", 30, 100},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gain := 2.0
+			weights := neural.Orthogonal(gain)(rand.New(rand.NewSource(1)), test.fanIn, test.fanOut)
+			rows, cols := weights.Dimensions()
+			if rows != test.fanOut || cols != test.fanIn {
+				t.Fatalf(
+					"expected a %dx%d matrix, instead got a %dx%d matrix",
+					test.fanOut, test.fanIn, rows, cols,
+				)
+			}
+
+			// The smaller of W * W^T and W^T * W should be gain^2 * I.
+			product := matrix.Multiply(weights.Transpose(), weights)
+			if rows < cols {
+				product = matrix.Multiply(weights, weights.Transpose())
+			}
+			size, _ := product.Dimensions()
+			for r := 0; r < size; r++ {
+				for c := 0; c < size; c++ {
+					expected := 0.0
+					if r == c {
+						expected = gain * gain
+					}
+					if math.Abs(product.Get(r, c)-expected) > 1e-9 {
+						t.Fatalf(
+							"expected the weights to be orthogonal, but entry (%d, %d) "+
+								"of their product with their transpose was %f",
+							r, c, product.Get(r, c),
+						)
+					}
+				}
+			}
+
+			// The closest orthogonal matrix W to the random matrix A is the
+			// one for which A * W^T is symmetric.
+			random := neural.StandardNormal()(rand.New(rand.NewSource(1)), test.fanIn, test.fanOut)
+			symmetric := matrix.Multiply(random, weights.Transpose())
+			if !matrix.EqualApprox(symmetric, symmetric.Transpose(), 1e-9, 1e-9) {
+				t.Fatalf("expected the weights to be the orthogonal matrix closest to the random one, instead %v", matrix.Diff(symmetric, symmetric.Transpose()))
+			}
+		})
+	}
+}
+
+func TestWithInitializer(t *testing.T) {
+	n := neural.New([]int{3, 2}, sigmoid, neural.WithInitializer(neural.Constant(0)))
+	output := n.Predict(matrix.NewFromSlice([]float64{1, 2, 3}, 3, 1))
+	expected := "0.5\n0.5"
+	if output.String() != expected {
+		t.Fatalf(
+			"expected a network with all weights set to 0 to predict:\n\n%s\n\ninstead it predicted:\n\n%s",
+			expected,
+			output,
+		)
+	}
+
+	t.Run("Wrong dimensions", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected New to panic when the initializer returns the wrong dimensions")
+			}
+		}()
+		wrong := func(rng *rand.Rand, fanIn, fanOut int) *matrix.Matrix {
+			return matrix.New(fanIn, fanOut)
+		}
+		neural.New([]int{3, 2}, sigmoid, neural.WithInitializer(wrong))
+	})
+}
@@ -2,53 +2,286 @@
 //
 // Created with reference to Sebastian Lague's amazing youtube series on neural
 // networks. 3Blue1Brown is also a great channel to reference.
+//
+// # Reproducibility
+//
+// The initial weights of a Network are drawn from a single generator, in a
+// fixed order, and the biases start at zero. Two networks created with the same WithSeed
+// option, layer sizes and Initializer are therefore identical, no matter
+// what else the program is doing. Data can be shuffled reproducibly in the
+// same way with mnist.Set.Shuffle.
+//
+// Without WithSeed or WithRand, the nth network created by the program is
+// seeded with n, so networks differ from each other but a program that
+// creates them in the same order gets the same networks every run.
 package neural
 
-import "github.com/Anthony-Fiddes/gonne/internal/matrix"
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
 
-// Network represents a neural network
-type Network struct {
+// Network represents a neural network whose weights, biases and activations
+// are all of type T. A Network of float32s takes half the memory of one of
+// float64s.
+type Network[T matrix.Float] struct {
 	layerSizes []int
-	weights    []*matrix.Matrix
-	biases     []*matrix.Matrix
-	Activation func(float64) float64
+	weights    []*matrix.Dense[T]
+	biases     []*matrix.Dense[T]
+	Activation func(T) T
+	// workspaces holds *workspace[T]s for predictions to reuse.
+	workspaces *sync.Pool
+	// prediction is the output of the last call to Predict, which the next
+	// call with a batch of the same size overwrites.
+	prediction *matrix.Dense[T]
+}
+
+// workspace holds the outputs of every layer but the last for a batch of a
+// given size, so that a prediction can reuse them rather than allocate new
+// matrices.
+type workspace[T matrix.Float] struct {
+	batch   int
+	outputs []*matrix.Dense[T]
+}
+
+// Option configures a Network created by New.
+type Option func(*options)
+
+type options struct {
+	initializer Initializer
+	rng         *rand.Rand
+}
+
+// networks counts the networks created without their own seed, which is
+// used to seed them.
+var networks int64
+
+// WithSeed makes the network draw all of its random numbers from a new
+// generator with the given seed, so that it is reproducible.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithRand makes the network draw all of its random numbers from the
+// supplied generator. The generator must not be used concurrently with the
+// network, since *rand.Rand is not safe for concurrent use.
+func WithRand(rng *rand.Rand) Option {
+	return func(o *options) {
+		o.rng = rng
+	}
+}
+
+// WithInitializer sets the Initializer used for the weights of every layer.
+// The default is StandardNormal.
+func WithInitializer(initializer Initializer) Option {
+	return func(o *options) {
+		o.initializer = initializer
+	}
+}
+
+// errorString represents an error in building a network
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
 }
 
+const (
+	// ErrTooFewLayers specifies that a network was given fewer than the two
+	// layers it needs for its input and output.
+	ErrTooFewLayers errorString = "neural: there must be at least 2 layers (one for input and one for output)"
+	// ErrInvalidLayerSize specifies that a layer was given a size less than
+	// or equal to 0.
+	ErrInvalidLayerSize errorString = "neural: layer sizes must be greater than 0"
+	// ErrMissingParameters specifies that saved weights or biases for a
+	// layer of a network could not be found.
+	ErrMissingParameters errorString = "neural: missing weights or biases"
+)
+
 // New returns a new neural network
-func New(layerSizes []int, activation func(float64) float64) *Network {
+//
+// Will panic if the layer sizes are invalid; see TryNew.
+func New[T matrix.Float](layerSizes []int, activation func(T) T, opts ...Option) *Network[T] {
+	net, err := TryNew(layerSizes, activation, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return net
+}
+
+// TryNew is like New, but returns an error instead of panicking if there are
+// too few layers (ErrTooFewLayers), a layer has no neurons
+// (ErrInvalidLayerSize), or the initializer returns weights with the wrong
+// dimensions (a *matrix.DimensionError).
+func TryNew[T matrix.Float](layerSizes []int, activation func(T) T, opts ...Option) (*Network[T], error) {
 	// There has to be at least two layers for input and output
 	if len(layerSizes) < 2 {
-		panic("neural: there must be at least 2 layers (one for input and one for output)")
+		return nil, ErrTooFewLayers
+	}
+	for i, size := range layerSizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("%w: layer %d has size %d", ErrInvalidLayerSize, i, size)
+		}
 	}
 
-	net := Network{layerSizes: layerSizes}
+	o := options{initializer: StandardNormal()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rng == nil {
+		o.rng = rand.New(rand.NewSource(atomic.AddInt64(&networks, 1) - 1))
+	}
+
+	net := Network[T]{layerSizes: layerSizes, workspaces: &sync.Pool{}}
 
 	netLayerSizes := make([]int, len(layerSizes))
 	copy(netLayerSizes, layerSizes)
 	net.layerSizes = netLayerSizes
 
-	net.weights = make([]*matrix.Matrix, 0, len(net.layerSizes)-1)
-	net.biases = make([]*matrix.Matrix, 0, len(net.layerSizes)-1)
+	net.weights = make([]*matrix.Dense[T], 0, len(net.layerSizes)-1)
+	net.biases = make([]*matrix.Dense[T], 0, len(net.layerSizes)-1)
 	for i := 1; i < len(net.layerSizes); i++ {
 		rows := net.layerSizes[i]
 		cols := net.layerSizes[i-1]
-		net.weights = append(net.weights, matrix.NewRandomNormal(rows, cols))
-		net.biases = append(net.biases, matrix.New(rows, 1))
+		weights := o.initializer(o.rng, cols, rows)
+		if r, c := weights.Dimensions(); r != rows || c != cols {
+			return nil, fmt.Errorf("neural: layer %d: %w", i, &matrix.DimensionError{
+				First:  [2]int{r, c},
+				Second: [2]int{rows, cols},
+				Reason: "the initializer must return weights with the dimensions the layer needs",
+			})
+		}
+		net.weights = append(net.weights, matrix.Convert[T](weights))
+		net.biases = append(net.biases, matrix.NewDense[T](rows, 1))
 	}
 
 	net.Activation = activation
 
+	return &net, nil
+}
+
+// Convert returns a copy of a network with its weights and biases converted
+// to another type. The copy uses the supplied activation function, or if it
+// is nil, the activation function of the original network with its input and
+// output converted.
+func Convert[To, From matrix.Float](n *Network[From], activation func(To) To) *Network[To] {
+	if activation == nil {
+		original := n.Activation
+		activation = func(x To) To {
+			return To(original(From(x)))
+		}
+	}
+
+	net := Network[To]{Activation: activation, workspaces: &sync.Pool{}}
+	net.layerSizes = make([]int, len(n.layerSizes))
+	copy(net.layerSizes, n.layerSizes)
+	net.weights = make([]*matrix.Dense[To], len(n.weights))
+	net.biases = make([]*matrix.Dense[To], len(n.biases))
+	for i := range n.weights {
+		net.weights[i] = matrix.Convert[To](n.weights[i])
+		net.biases[i] = matrix.Convert[To](n.biases[i])
+	}
 	return &net
 }
 
 // Predict takes an input matrix and produces a matrix describing the
 // probabilities for each possible output.
-func (n *Network) Predict(input *matrix.Matrix) *matrix.Matrix {
-	result := input
-	for i := 0; i < len(n.weights); i++ {
-		result = matrix.Multiply(n.weights[i], result)
-		result = matrix.Add(result, n.biases[i])
-		result = matrix.Map(result, n.Activation)
+//
+// Each column of the input is a separate sample, so a whole batch can be
+// predicted at once, producing one column of output per sample.
+//
+// The result belongs to the network, and is only valid until the next call
+// to Predict, which overwrites it; Clone it to keep it for longer. In return,
+// once the network has predicted a batch of the same size, Predict makes no
+// heap allocations. Unlike PredictTo, it isn't safe to call from several
+// goroutines at once.
+func (n *Network[T]) Predict(input *matrix.Dense[T]) *matrix.Dense[T] {
+	_, batch := input.Dimensions()
+	outputs := n.layerSizes[len(n.layerSizes)-1]
+	if n.prediction == nil {
+		n.prediction = matrix.NewDense[T](outputs, batch)
+	} else if rows, cols := n.prediction.Dimensions(); rows != outputs || cols != batch {
+		n.prediction = matrix.NewDense[T](outputs, batch)
+	}
+	n.PredictTo(n.prediction, input)
+	return n.prediction
+}
+
+// PredictTo is like Predict, but stores the output in dst, which must have a
+// row for every output neuron and a column for every sample. Once the
+// network has predicted a batch of the same size, PredictTo makes no heap
+// allocations, so it suits predicting in a loop.
+//
+// It is safe to call from several goroutines at once; each draws its own
+// workspace from the network.
+func (n *Network[T]) PredictTo(dst, input *matrix.Dense[T]) {
+	_, batch := input.Dimensions()
+	ws := n.workspace(batch)
+	weighted := n.layerOutput(ws, dst, 0)
+	matrix.MulInto(weighted, n.weights[0], input)
+	n.predictFrom(ws, dst, weighted)
+	n.workspaces.Put(ws)
+}
+
+// PredictSparse is like Predict, but takes a sparse input, such as a batch
+// of MNIST images, which are mostly zeros. The first layer only does work
+// for the stored entries, but that work doesn't vectorize, so it is only
+// faster than Predict when the input is very sparse. Use a CSC input, with
+// a column per sample; a CSR input is much slower.
+func (n *Network[T]) PredictSparse(input matrix.Sparse[T]) *matrix.Dense[T] {
+	weighted := matrix.MultiplyDenseSparse(n.weights[0], input)
+	_, batch := weighted.Dimensions()
+	result := weighted
+	if len(n.weights) > 1 {
+		result = matrix.NewDense[T](n.layerSizes[len(n.layerSizes)-1], batch)
 	}
+	ws := n.workspace(batch)
+	n.predictFrom(ws, result, weighted)
+	n.workspaces.Put(ws)
 	return result
 }
+
+// workspace returns a workspace for a batch of the given size, reusing one
+// from the pool if it has the right size.
+func (n *Network[T]) workspace(batch int) *workspace[T] {
+	ws, _ := n.workspaces.Get().(*workspace[T])
+	if ws != nil && ws.batch == batch {
+		return ws
+	}
+	ws = &workspace[T]{batch: batch, outputs: make([]*matrix.Dense[T], len(n.weights))}
+	for i := 0; i < len(n.weights)-1; i++ {
+		ws.outputs[i] = matrix.NewDense[T](n.layerSizes[i+1], batch)
+	}
+	return ws
+}
+
+// layerOutput returns the matrix that the ith layer writes its output to,
+// which is dst for the last layer.
+func (n *Network[T]) layerOutput(ws *workspace[T], dst *matrix.Dense[T], i int) *matrix.Dense[T] {
+	if i == len(n.weights)-1 {
+		return dst
+	}
+	return ws.outputs[i]
+}
+
+// predictFrom finishes a prediction from the product of the first layer's
+// weights and the input, storing it in dst. The hidden layers write their
+// outputs to the workspace.
+func (n *Network[T]) predictFrom(ws *workspace[T], dst, weighted *matrix.Dense[T]) {
+	output := weighted
+	for i := 0; i < len(n.weights); i++ {
+		if i > 0 {
+			previous := output
+			output = n.layerOutput(ws, dst, i)
+			matrix.MulInto(output, n.weights[i], previous)
+		}
+		matrix.AddTo(output, output, n.biases[i])
+		matrix.MapTo(output, output, n.Activation)
+	}
+}
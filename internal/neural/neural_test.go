@@ -1,7 +1,10 @@
 package neural_test
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/Anthony-Fiddes/gonne/internal/matrix"
@@ -28,3 +31,207 @@ func TestPredict(t *testing.T) {
 		output,
 	)
 }
+
+func sigmoid32(x float32) float32 {
+	return 1 / (1 + float32(math.Exp(float64(-x))))
+}
+
+func TestPredictBatch(t *testing.T) {
+	inputSize := 10
+	outputSize := 5
+	batchSize := 3
+	n := neural.New([]int{inputSize, 7, outputSize}, sigmoid)
+	batch := matrix.NewRandomNormal(inputSize, batchSize)
+	output := n.Predict(batch).Clone()
+	rows, cols := output.Dimensions()
+	if rows != outputSize || cols != batchSize {
+		t.Fatalf(
+			"expected a batch of %d inputs to produce a %dx%d output, instead it produced a %dx%d output",
+			batchSize, outputSize, batchSize, rows, cols,
+		)
+	}
+	for c := 0; c < batchSize; c++ {
+		single := n.Predict(batch.Slice(0, inputSize, c, c+1))
+		for r := 0; r < outputSize; r++ {
+			if math.Abs(single.Get(r, 0)-output.Get(r, c)) > 1e-12 {
+				t.Fatalf(
+					"expected sample %d of the batch to produce the same output as "+
+						"predicting it on its own, instead got:\n\n%s\n\nand:\n\n%s",
+					c, output.ColView(c), single,
+				)
+			}
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	inputSize := 10
+	outputSize := 5
+	input := matrix.NewRandomNormal(inputSize, 1)
+	n := neural.New([]int{inputSize, 8, outputSize}, sigmoid)
+
+	tests := []struct {
+		name       string
+		activation func(float32) float32
+	}{
+		{"Supplied activation", sigmoid32},
+		{"Converted activation", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n32 := neural.Convert[float32](n, test.activation)
+			expected := n.Predict(input)
+			output := n32.Predict(matrix.Convert[float32](input))
+			for r := 0; r < outputSize; r++ {
+				if math.Abs(expected.Get(r, 0)-float64(output.Get(r, 0))) > 1e-5 {
+					t.Fatalf(
+						"expected the float32 network to produce:\n\n%s\n\ninstead it produced:\n\n%s",
+						expected,
+						output,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestSeed(t *testing.T) {
+	layerSizes := []int{20, 10, 5}
+	input := matrix.NewRandomNormal(20, 3)
+	predict := func(opts ...neural.Option) *matrix.Matrix {
+		return neural.New(layerSizes, sigmoid, opts...).Predict(input)
+	}
+
+	tests := []struct {
+		name          string
+		first, second []neural.Option
+		same          bool
+	}{
+		{"SameSeed", []neural.Option{neural.WithSeed(7)}, []neural.Option{neural.WithSeed(7)}, true},
+		{"DifferentSeeds", []neural.Option{neural.WithSeed(7)}, []neural.Option{neural.WithSeed(8)}, false},
+		{
+			"SeedAndRand",
+			[]neural.Option{neural.WithSeed(7), neural.WithInitializer(neural.HeNormal())},
+			[]neural.Option{neural.WithRand(rand.New(rand.NewSource(7))), neural.WithInitializer(neural.HeNormal())},
+			true,
+		},
+		{"Default", nil, nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first, second := predict(test.first...), predict(test.second...)
+			if same := matrix.Equal(first, second); same != test.same {
+				t.Fatalf("expected identical networks to be %v, instead got predictions:\n%s\nand\n%s", test.same, first, second)
+			}
+		})
+	}
+}
+
+func TestTryNew(t *testing.T) {
+	wrong := func(rng *rand.Rand, fanIn, fanOut int) *matrix.Matrix {
+		return matrix.New(fanIn, fanOut)
+	}
+	tests := []struct {
+		name       string
+		layerSizes []int
+		opts       []neural.Option
+		want       error
+	}{
+		{"Valid", []int{3, 2}, nil, nil},
+		{"TooFewLayers", []int{3}, nil, neural.ErrTooFewLayers},
+		{"EmptyLayer", []int{3, 0, 2}, nil, neural.ErrInvalidLayerSize},
+		{"WrongInitializer", []int{3, 2}, []neural.Option{neural.WithInitializer(wrong)}, matrix.ErrDimensionMismatch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, err := neural.TryNew(test.layerSizes, sigmoid, test.opts...)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("expected the error %v, instead got %v", test.want, err)
+			}
+			if (n == nil) != (test.want != nil) {
+				t.Fatalf("expected a network only when there is no error, instead got %v", n)
+			}
+		})
+	}
+}
+
+func TestPredictSparse(t *testing.T) {
+	n := neural.New([]int{6, 4, 3}, sigmoid, neural.WithSeed(1))
+	input := matrix.NewFromSlice([]float64{
+		0, 1, 0,
+		0, 0, 0,
+		2, 0, 0,
+		0, 0, 0,
+		0, 0, 3,
+		0, 0, 0,
+	}, 6, 3)
+	expected := n.Predict(input)
+	for name, sparse := range map[string]matrix.Sparse[float64]{"CSR": matrix.ToCSR(input), "CSC": matrix.ToCSC(input)} {
+		if result := n.PredictSparse(sparse); !matrix.EqualApprox(result, expected, 1e-12, 1e-12) {
+			t.Fatalf("expected the %s prediction to match the dense one, instead %v", name, matrix.Diff(result, expected))
+		}
+	}
+}
+
+func TestPredictTo(t *testing.T) {
+	tests := []struct {
+		name       string
+		layerSizes []int
+		batch      int
+	}{
+		{name: "single sample", layerSizes: []int{8, 5, 3}, batch: 1},
+		{name: "batch", layerSizes: []int{8, 5, 3}, batch: 4},
+		{name: "no hidden layers", layerSizes: []int{8, 3}, batch: 2},
+		{name: "large batch", layerSizes: []int{784, 100, 10}, batch: 64},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n := neural.New(test.layerSizes, sigmoid, neural.WithSeed(1))
+			input := matrix.NewRandomUniform(test.layerSizes[0], test.batch, 0, 1)
+			expected := n.Predict(input).Clone()
+			dst := matrix.NewDense[float64](test.layerSizes[len(test.layerSizes)-1], test.batch)
+			n.PredictTo(dst, input)
+			if !matrix.EqualApprox(dst, expected, 1e-12, 1e-12) {
+				t.Fatalf("expected PredictTo to match Predict, instead %v", matrix.Diff(dst, expected))
+			}
+			if raceEnabled {
+				return
+			}
+			allocs := testing.AllocsPerRun(10, func() {
+				n.PredictTo(dst, input)
+			})
+			if allocs != 0 {
+				t.Fatalf("expected PredictTo to make no allocations once warmed up, instead it made %v", allocs)
+			}
+			allocs = testing.AllocsPerRun(10, func() {
+				n.Predict(input)
+			})
+			if allocs != 0 {
+				t.Fatalf("expected Predict to make no allocations once warmed up, instead it made %v", allocs)
+			}
+			if result := n.Predict(input); !matrix.EqualApprox(result, expected, 1e-12, 1e-12) {
+				t.Fatalf("expected reusing the result of Predict to give the same prediction, instead %v", matrix.Diff(result, expected))
+			}
+		})
+	}
+}
+
+func BenchmarkPredict(b *testing.B) {
+	for _, batch := range []int{1, 64} {
+		n := neural.New([]int{784, 100, 10}, sigmoid, neural.WithSeed(1))
+		input := matrix.NewRandomUniform(784, batch, 0, 1)
+		dst := matrix.NewDense[float64](10, batch)
+		b.Run(fmt.Sprintf("Predict/batch=%d", batch), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				n.Predict(input)
+			}
+		})
+		b.Run(fmt.Sprintf("PredictTo/batch=%d", batch), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				n.PredictTo(dst, input)
+			}
+		})
+	}
+}
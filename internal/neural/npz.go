@@ -0,0 +1,85 @@
+package neural
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/npy"
+)
+
+// WeightsKey and BiasesKey return the names of the arrays that hold the
+// weights and biases of layer i of a network in an .npz archive, where
+// layer 0 connects the input to the first hidden layer. The weights of
+// layer i are layerSizes[i+1] x layerSizes[i] and its biases are
+// layerSizes[i+1] x 1, or a 1-D array of that length.
+func WeightsKey(i int) string {
+	return fmt.Sprintf("weights_%d", i)
+}
+
+// BiasesKey returns the name of the array that holds the biases of layer i.
+// See WeightsKey.
+func BiasesKey(i int) string {
+	return fmt.Sprintf("biases_%d", i)
+}
+
+// ReadWeights replaces the weights and biases of every layer of the network
+// with those in an .npz archive, keyed by WeightsKey and BiasesKey, such as
+// one saved from Python with numpy.savez. Other arrays in the archive are
+// ignored.
+//
+// The network is only changed if every layer is found with the right
+// dimensions. Otherwise the error wraps ErrMissingParameters or is a
+// *matrix.DimensionError.
+func (n *Network[T]) ReadWeights(r io.ReaderAt, size int64) error {
+	arrays, err := npy.ReadArchiveDense[T](r, size)
+	if err != nil {
+		return fmt.Errorf("neural: %w", err)
+	}
+
+	weights := make([]*matrix.Dense[T], len(n.weights))
+	biases := make([]*matrix.Dense[T], len(n.biases))
+	for i := range n.weights {
+		w, wOk := arrays[WeightsKey(i)]
+		b, bOk := arrays[BiasesKey(i)]
+		if !wOk || !bOk {
+			return fmt.Errorf("%w: layer %d needs %q and %q", ErrMissingParameters, i, WeightsKey(i), BiasesKey(i))
+		}
+		if err := parametersErr(n.weights[i], w, "the saved weights must have the dimensions the layer needs"); err != nil {
+			return fmt.Errorf("neural: layer %d: %w", i, err)
+		}
+		if err := parametersErr(n.biases[i], b, "the saved biases must have the dimensions the layer needs"); err != nil {
+			return fmt.Errorf("neural: layer %d: %w", i, err)
+		}
+		weights[i], biases[i] = w, b
+	}
+	n.weights, n.biases = weights, biases
+	return nil
+}
+
+func parametersErr[T matrix.Float](current, saved *matrix.Dense[T], reason string) error {
+	rows, cols := current.Dimensions()
+	if r, c := saved.Dimensions(); r != rows || c != cols {
+		return &matrix.DimensionError{
+			First:  [2]int{r, c},
+			Second: [2]int{rows, cols},
+			Reason: reason,
+		}
+	}
+	return nil
+}
+
+// WriteWeights writes the weights and biases of every layer of the network
+// as an .npz archive, keyed by WeightsKey and BiasesKey. It can be loaded
+// with ReadWeights or, in Python, with numpy.load.
+func (n *Network[T]) WriteWeights(w io.Writer) error {
+	arrays := make(map[string]*matrix.Dense[T], 2*len(n.weights))
+	for i := range n.weights {
+		arrays[WeightsKey(i)] = n.weights[i]
+		arrays[BiasesKey(i)] = n.biases[i]
+	}
+	if err := npy.WriteArchive(w, arrays); err != nil {
+		return fmt.Errorf("neural: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package neural_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/neural"
+	"github.com/Anthony-Fiddes/gonne/internal/npy"
+)
+
+func TestWeights(t *testing.T) {
+	layers := []int{4, 3, 2}
+	saved := neural.New(layers, sigmoid, neural.WithSeed(1))
+	var buffer bytes.Buffer
+	if err := saved.WriteWeights(&buffer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded := neural.New(layers, sigmoid, neural.WithSeed(2))
+	if err := loaded.ReadWeights(bytes.NewReader(buffer.Bytes()), int64(buffer.Len())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input := matrix.NewRandomNormal(4, 5)
+	if expected, result := saved.Predict(input), loaded.Predict(input); !matrix.Equal(result, expected) {
+		t.Fatalf("expected the loaded network to predict:\n\n%s\n\ninstead it predicted:\n\n%s", expected, result)
+	}
+
+	loaded32 := neural.New(layers, func(x float32) float32 { return float32(sigmoid(float64(x))) })
+	if err := loaded32.ReadWeights(bytes.NewReader(buffer.Bytes()), int64(buffer.Len())); err != nil {
+		t.Fatalf("unexpected error loading float32 weights: %v", err)
+	}
+}
+
+func TestReadWeights(t *testing.T) {
+	// Biases saved from Python are usually 1-D.
+	arrays := map[string]*matrix.Matrix{
+		neural.WeightsKey(0): matrix.Parse("1 0; 0 1; 1 1"),
+		neural.BiasesKey(0):  matrix.Parse("0; 0; -1"),
+		"optimizer_state":    matrix.Parse("1 2 3"),
+	}
+	var buffer bytes.Buffer
+	if err := npy.WriteArchive(&buffer, arrays); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n := neural.New([]int{2, 3}, func(x float64) float64 { return x })
+	if err := n.ReadWeights(bytes.NewReader(buffer.Bytes()), int64(buffer.Len())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result, expected := n.Predict(matrix.Parse("2; 3")), matrix.Parse("2; 3; 4"); !matrix.Equal(result, expected) {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+	}
+
+	tests := []struct {
+		name   string
+		layers []int
+		want   error
+	}{
+		{"Missing layer", []int{2, 3, 1}, neural.ErrMissingParameters},
+		{"Wrong dimensions", []int{3, 3}, matrix.ErrDimensionMismatch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n := neural.New(test.layers, sigmoid, neural.WithSeed(1))
+			input := matrix.NewRandomNormal(test.layers[0], 1)
+			before := n.Predict(input).Clone()
+			err := n.ReadWeights(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+			if !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+			if after := n.Predict(input); !matrix.Equal(after, before) {
+				t.Fatalf("expected a failed read to leave the network unchanged, instead it predicts:\n\n%s\n\nrather than:\n\n%s", after, before)
+			}
+		})
+	}
+}
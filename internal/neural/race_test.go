@@ -0,0 +1,8 @@
+//go:build race
+
+package neural_test
+
+// raceEnabled reports whether the tests were built with the race detector,
+// which makes sync.Pool drop items at random, so allocations can't be
+// counted.
+const raceEnabled = true
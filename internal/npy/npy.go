@@ -0,0 +1,434 @@
+// Package npy reads and writes NumPy's .npy files and .npz archives, so that
+// weights and datasets can be exchanged with Python without custom scripts.
+//
+// An .npy file holds a single array. Arrays of float32s, float64s and
+// uint8s are supported, in either C (row-major) or Fortran (column-major)
+// order. A 2-D array is read as a matrix with the same rows and cols, and a
+// 1-D array of length n as an n x 1 column vector, which is how a Network
+// stores its biases. Arrays with more axes can be read as a matrix.Tensor.
+package npy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+// errorString represents an error in reading or writing NumPy data
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrInvalidMagicNumber specifies that the data being read did not
+	// start with the magic string of an .npy file.
+	ErrInvalidMagicNumber errorString = "npy: invalid magic number"
+	// ErrInvalidHeader specifies that the header describing an array could
+	// not be understood.
+	ErrInvalidHeader errorString = "npy: invalid header"
+	// ErrUnsupportedDType specifies that an array holds a type of data other
+	// than float32, float64 or uint8.
+	ErrUnsupportedDType errorString = "npy: unsupported dtype"
+	// ErrInvalidShape specifies that an array's shape can't be held by the
+	// type it is being read into, for example because it has more than 2
+	// axes or an axis of length 0.
+	ErrInvalidShape errorString = "npy: invalid shape"
+	// ErrUnrepresentable specifies that a value can't be written with the
+	// chosen dtype, such as 0.5 or 256 as a uint8.
+	ErrUnrepresentable errorString = "npy: value cannot be represented by the dtype"
+)
+
+const (
+	unexpectedReadErr  = "npy: unexpected error while reading: %w"
+	unexpectedWriteErr = "npy: unexpected error while writing: %w"
+)
+
+// DType is the type of the entries of an array.
+type DType int
+
+const (
+	// Float64 is NumPy's float64, or '<f8'.
+	Float64 DType = iota
+	// Float32 is NumPy's float32, or '<f4'.
+	Float32
+	// Uint8 is NumPy's uint8, or '|u1', as used for pixels.
+	Uint8
+)
+
+func (d DType) String() string {
+	switch d {
+	case Float64:
+		return "float64"
+	case Float32:
+		return "float32"
+	case Uint8:
+		return "uint8"
+	}
+	return fmt.Sprintf("DType(%d)", int(d))
+}
+
+// descr returns the description of the dtype in a header, in little-endian
+// byte order.
+func (d DType) descr() string {
+	switch d {
+	case Float32:
+		return "<f4"
+	case Uint8:
+		return "|u1"
+	}
+	return "<f8"
+}
+
+func (d DType) size() int {
+	switch d {
+	case Float32:
+		return 4
+	case Uint8:
+		return 1
+	}
+	return 8
+}
+
+// parseDescr returns the dtype and byte order described in a header.
+func parseDescr(descr string) (DType, binary.ByteOrder, error) {
+	if len(descr) != 3 {
+		return 0, nil, fmt.Errorf("%w %q", ErrUnsupportedDType, descr)
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	switch descr[0] {
+	case '<', '|', '=':
+	case '>':
+		order = binary.BigEndian
+	default:
+		return 0, nil, fmt.Errorf("%w %q", ErrUnsupportedDType, descr)
+	}
+	switch descr[1:] {
+	case "f8":
+		return Float64, order, nil
+	case "f4":
+		return Float32, order, nil
+	case "u1":
+		return Uint8, order, nil
+	}
+	return 0, nil, fmt.Errorf("%w %q", ErrUnsupportedDType, descr)
+}
+
+const magic = "\x93NUMPY"
+
+// header is the description of an array that precedes its data.
+type header struct {
+	dtype        DType
+	order        binary.ByteOrder
+	fortranOrder bool
+	shape        []int
+}
+
+var (
+	descrPattern   = regexp.MustCompile(`['"]descr['"]\s*:\s*['"]([^'"]*)['"]`)
+	fortranPattern = regexp.MustCompile(`['"]fortran_order['"]\s*:\s*(True|False)`)
+	shapePattern   = regexp.MustCompile(`['"]shape['"]\s*:\s*\(([^)]*)\)`)
+)
+
+// maxHeaderLength is the longest header that is read, which is the same
+// limit numpy.load has by default. A header describing any array this
+// package can read is far shorter.
+const maxHeaderLength = 10000
+
+func readHeader(r io.Reader) (header, error) {
+	prefix := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return header{}, fmt.Errorf(unexpectedReadErr, err)
+	}
+	if string(prefix[:len(magic)]) != magic {
+		return header{}, ErrInvalidMagicNumber
+	}
+
+	// Version 1 stores the length of the header in 2 bytes, and later
+	// versions in 4.
+	var length int
+	switch major := prefix[len(magic)]; major {
+	case 1:
+		var l uint16
+		if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+			return header{}, fmt.Errorf(unexpectedReadErr, err)
+		}
+		length = int(l)
+	case 2, 3:
+		var l uint32
+		if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+			return header{}, fmt.Errorf(unexpectedReadErr, err)
+		}
+		length = int(l)
+	default:
+		return header{}, fmt.Errorf("%w: unknown version %d", ErrInvalidHeader, major)
+	}
+	if length > maxHeaderLength {
+		return header{}, fmt.Errorf("%w: the header is %d bytes long, the most allowed is %d", ErrInvalidHeader, length, maxHeaderLength)
+	}
+	text := make([]byte, length)
+	if _, err := io.ReadFull(r, text); err != nil {
+		return header{}, fmt.Errorf(unexpectedReadErr, err)
+	}
+	return parseHeader(string(text))
+}
+
+// parseHeader parses the Python dictionary literal that describes an array.
+func parseHeader(text string) (header, error) {
+	descr := descrPattern.FindStringSubmatch(text)
+	fortran := fortranPattern.FindStringSubmatch(text)
+	shape := shapePattern.FindStringSubmatch(text)
+	if descr == nil || fortran == nil || shape == nil {
+		return header{}, fmt.Errorf("%w %q", ErrInvalidHeader, strings.TrimSpace(text))
+	}
+
+	h := header{fortranOrder: fortran[1] == "True", shape: []int{}}
+	var err error
+	if h.dtype, h.order, err = parseDescr(descr[1]); err != nil {
+		return header{}, err
+	}
+	for _, field := range strings.Split(shape[1], ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		length, err := strconv.Atoi(field)
+		if err != nil || length < 0 {
+			return header{}, fmt.Errorf("%w: invalid shape (%s)", ErrInvalidHeader, shape[1])
+		}
+		h.shape = append(h.shape, length)
+	}
+	return h, nil
+}
+
+// chunkSize is the most bytes of data that are read at once. Reading the data
+// a chunk at a time means that a file whose header claims more data than it
+// holds fails once the data runs out, rather than allocating all of it up
+// front.
+const chunkSize = 1 << 16
+
+// readData reads size entries of the type in the header and converts them
+// to T.
+func readData[T matrix.Float](r io.Reader, h header, size int) ([]T, error) {
+	perChunk := chunkSize / h.dtype.size()
+	if size < perChunk {
+		perChunk = size
+	}
+	raw := make([]byte, perChunk*h.dtype.size())
+	data := make([]T, 0, perChunk)
+	for len(data) < size {
+		n := size - len(data)
+		if n > perChunk {
+			n = perChunk
+		}
+		if _, err := io.ReadFull(r, raw[:n*h.dtype.size()]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf(unexpectedReadErr, err)
+		}
+		for i := 0; i < n; i++ {
+			switch h.dtype {
+			case Float64:
+				data = append(data, T(math.Float64frombits(h.order.Uint64(raw[8*i:]))))
+			case Float32:
+				data = append(data, T(math.Float32frombits(h.order.Uint32(raw[4*i:]))))
+			case Uint8:
+				data = append(data, T(raw[i]))
+			}
+		}
+	}
+	return data, nil
+}
+
+// ReadTensor reads an .npy file into a tensor with the same shape, converting
+// its entries to T. A 0-D array, which holds a single value, becomes a
+// tensor of shape 1. The error wraps ErrInvalidShape if any axis has length
+// 0, since a tensor can't be empty, or if the array is too large to hold,
+// and io.ErrUnexpectedEOF if the file ends before the data does.
+func ReadTensor[T matrix.Float](r io.Reader) (*matrix.Tensor[T], error) {
+	h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	shape := h.shape
+	if len(shape) == 0 {
+		shape = []int{1}
+	}
+	size := 1
+	for _, length := range shape {
+		if length == 0 {
+			return nil, fmt.Errorf("%w: the array is empty (%v)", ErrInvalidShape, h.shape)
+		}
+		if size > math.MaxInt/h.dtype.size()/length {
+			return nil, fmt.Errorf("%w: the array is too large (%v)", ErrInvalidShape, h.shape)
+		}
+		size *= length
+	}
+	data, err := readData[T](r, h, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if !h.fortranOrder {
+		return matrix.NewTensorFromSlice(data, shape...), nil
+	}
+	// Fortran order is the row-major order of the transpose, which has the
+	// axes reversed.
+	reversed := make([]int, len(shape))
+	for i, length := range shape {
+		reversed[len(shape)-1-i] = length
+	}
+	return matrix.NewTensorFromSlice(data, reversed...).Transpose().Clone(), nil
+}
+
+// ReadDense reads an .npy file holding a 1-D or 2-D array into a matrix,
+// converting its entries to T. A 1-D array of length n becomes an n x 1
+// column vector. The error wraps ErrInvalidShape if the array has more than
+// 2 axes or is empty.
+func ReadDense[T matrix.Float](r io.Reader) (*matrix.Dense[T], error) {
+	t, err := ReadTensor[T](r)
+	if err != nil {
+		return nil, err
+	}
+	switch shape := t.Shape(); len(shape) {
+	case 1:
+		return t.Reshape(shape[0], 1).Matrix(), nil
+	case 2:
+		return t.Matrix(), nil
+	default:
+		return nil, fmt.Errorf("%w: a matrix can't hold an array with shape %v", ErrInvalidShape, shape)
+	}
+}
+
+// Read reads an .npy file holding a 1-D or 2-D array into a float64 matrix.
+// See ReadDense.
+func Read(r io.Reader) (*matrix.Matrix, error) {
+	return ReadDense[float64](r)
+}
+
+// Option configures how an array is written.
+type Option func(*options)
+
+type options struct {
+	dtype        DType
+	fortranOrder bool
+}
+
+// WithDType writes the entries as the given dtype. By default they are
+// written as the type of the matrix, Float64 or Float32.
+func WithDType(dtype DType) Option {
+	return func(o *options) {
+		o.dtype = dtype
+	}
+}
+
+// WithFortranOrder writes the entries in Fortran (column-major) order
+// rather than C (row-major) order.
+func WithFortranOrder() Option {
+	return func(o *options) {
+		o.fortranOrder = true
+	}
+}
+
+func newOptions[T matrix.Float](opts []Option) options {
+	o := options{dtype: Float64}
+	var zero T
+	if _, ok := any(zero).(float32); ok {
+		o.dtype = Float32
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// headerAlignment is the multiple of bytes that the magic string, version
+// and header are padded to, so that the data is aligned.
+const headerAlignment = 64
+
+// formatHeader returns everything that precedes the data of an array with
+// the given dtype, order and shape.
+func formatHeader(dtype DType, fortranOrder bool, shape []int) []byte {
+	fortran := "False"
+	if fortranOrder {
+		fortran = "True"
+	}
+	dims := make([]string, len(shape))
+	for i, length := range shape {
+		dims[i] = strconv.Itoa(length)
+	}
+	tuple := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		tuple += ","
+	}
+	text := fmt.Sprintf("{'descr': '%s', 'fortran_order': %s, 'shape': (%s), }", dtype.descr(), fortran, tuple)
+
+	// The header ends with a newline after enough spaces to align the data.
+	var buffer bytes.Buffer
+	buffer.WriteString(magic)
+	prefix := len(magic) + 2 + 2
+	if prefix+len(text)+1 > math.MaxUint16 {
+		prefix = len(magic) + 2 + 4
+	}
+	padding := (headerAlignment - (prefix+len(text)+1)%headerAlignment) % headerAlignment
+	text += strings.Repeat(" ", padding) + "\n"
+	if prefix == len(magic)+2+2 {
+		buffer.Write([]byte{1, 0})
+		binary.Write(&buffer, binary.LittleEndian, uint16(len(text)))
+	} else {
+		buffer.Write([]byte{2, 0})
+		binary.Write(&buffer, binary.LittleEndian, uint32(len(text)))
+	}
+	buffer.WriteString(text)
+	return buffer.Bytes()
+}
+
+// WriteTensor writes a tensor as an .npy file with the same shape. The
+// error wraps ErrUnrepresentable if an entry can't be stored as the chosen
+// dtype.
+func WriteTensor[T matrix.Float](w io.Writer, t *matrix.Tensor[T], opts ...Option) error {
+	o := newOptions[T](opts)
+	values := t.Values()
+	if o.fortranOrder {
+		values = t.Transpose().Values()
+	}
+
+	raw := make([]byte, len(values)*o.dtype.size())
+	for i, value := range values {
+		switch o.dtype {
+		case Float64:
+			binary.LittleEndian.PutUint64(raw[8*i:], math.Float64bits(float64(value)))
+		case Float32:
+			binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(float32(value)))
+		case Uint8:
+			if value < 0 || value > math.MaxUint8 || value != T(math.Trunc(float64(value))) {
+				return fmt.Errorf("%w: %v is not a %v", ErrUnrepresentable, value, o.dtype)
+			}
+			raw[i] = uint8(value)
+		default:
+			return fmt.Errorf("%w %v", ErrUnsupportedDType, o.dtype)
+		}
+	}
+
+	if _, err := w.Write(formatHeader(o.dtype, o.fortranOrder, t.Shape())); err != nil {
+		return fmt.Errorf(unexpectedWriteErr, err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return fmt.Errorf(unexpectedWriteErr, err)
+	}
+	return nil
+}
+
+// Write writes a matrix as a 2-D .npy file. See WriteTensor.
+func Write[T matrix.Float](w io.Writer, m *matrix.Dense[T], opts ...Option) error {
+	return WriteTensor(w, matrix.FromMatrix(m), opts...)
+}
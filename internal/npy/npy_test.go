@@ -0,0 +1,206 @@
+package npy_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/npy"
+)
+
+// npyFile returns an .npy file laid out the way numpy.save writes it, with
+// the given header dictionary and data.
+func npyFile(dict string, data interface{}, order binary.ByteOrder) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("\x93NUMPY\x01\x00")
+	padding := 64 - (10+len(dict)+1)%64
+	text := dict + strings.Repeat(" ", padding%64) + "\n"
+	binary.Write(&buffer, binary.LittleEndian, uint16(len(text)))
+	buffer.WriteString(text)
+	binary.Write(&buffer, order, data)
+	return buffer.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	expected := matrix.Parse("1 2 3\n4 5 6")
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{
+			"float64",
+			npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }", []float64{1, 2, 3, 4, 5, 6}, binary.LittleEndian),
+		},
+		{
+			"float32",
+			npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (2, 3), }", []float32{1, 2, 3, 4, 5, 6}, binary.LittleEndian),
+		},
+		{
+			"uint8",
+			npyFile("{'descr': '|u1', 'fortran_order': False, 'shape': (2, 3), }", []uint8{1, 2, 3, 4, 5, 6}, binary.LittleEndian),
+		},
+		{
+			"Big endian",
+			npyFile("{'descr': '>f8', 'fortran_order': False, 'shape': (2, 3), }", []float64{1, 2, 3, 4, 5, 6}, binary.BigEndian),
+		},
+		{
+			"Fortran order",
+			npyFile("{'descr': '<f8', 'fortran_order': True, 'shape': (2, 3), }", []float64{1, 4, 2, 5, 3, 6}, binary.LittleEndian),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := npy.Read(bytes.NewReader(test.file))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !matrix.Equal(result, expected) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+			}
+		})
+	}
+
+	vector := npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (3,), }", []float32{1, 2, 3}, binary.LittleEndian)
+	result, err := npy.ReadDense[float32](bytes.NewReader(vector))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := matrix.ParseDense[float32]("1; 2; 3"); !matrix.Equal(result, expected) {
+		t.Fatalf("expected a 1-D array to be read as a column vector:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+	}
+}
+
+func TestReadTensor(t *testing.T) {
+	data := make([]float64, 24)
+	for i := range data {
+		data[i] = float64(i)
+	}
+	expected := matrix.NewTensorFromSlice(data, 2, 3, 4)
+	// The Fortran order of the same array is the C order of its transpose.
+	fortran := expected.Transpose().Values()
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{"C order", npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3, 4), }", data, binary.LittleEndian)},
+		{"Fortran order", npyFile("{'descr': '<f8', 'fortran_order': True, 'shape': (2, 3, 4), }", fortran, binary.LittleEndian)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := npy.ReadTensor[float64](bytes.NewReader(test.file))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Shape(), expected.Shape()) || !reflect.DeepEqual(result.Values(), expected.Values()) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", expected, result)
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	valid := npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }", make([]float64, 6), binary.LittleEndian)
+	tests := []struct {
+		name string
+		file []byte
+		want error
+	}{
+		{"Magic number", append([]byte("\x93NUMPZ"), valid[6:]...), npy.ErrInvalidMagicNumber},
+		{"Huge header", []byte("\x93NUMPY\x02\x00\xff\xff\xff\xff{'descr': '<f8'"), npy.ErrInvalidHeader},
+		{"Truncated data", valid[:len(valid)-1], io.ErrUnexpectedEOF},
+		{"Truncated chunk", valid[:len(valid)-48], io.ErrUnexpectedEOF},
+		{"Huge shape", npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (2000000000000,), }", []float64{1}, binary.LittleEndian), io.ErrUnexpectedEOF},
+		{"Overflowing shape", npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (4611686018427387904, 4), }", []float64{1}, binary.LittleEndian), npy.ErrInvalidShape},
+		{"No shape", npyFile("{'descr': '<f8', 'fortran_order': False, }", []float64{1}, binary.LittleEndian), npy.ErrInvalidHeader},
+		{"Bad shape", npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (a,), }", []float64{1}, binary.LittleEndian), npy.ErrInvalidHeader},
+		{"Complex", npyFile("{'descr': '<c16', 'fortran_order': False, 'shape': (1,), }", []float64{1, 2}, binary.LittleEndian), npy.ErrUnsupportedDType},
+		{"Int64", npyFile("{'descr': '<i8', 'fortran_order': False, 'shape': (1,), }", []int64{1}, binary.LittleEndian), npy.ErrUnsupportedDType},
+		{"Empty", npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (0, 3), }", []float64{}, binary.LittleEndian), npy.ErrInvalidShape},
+		{"3-D", npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (1, 1, 1), }", []float64{1}, binary.LittleEndian), npy.ErrInvalidShape},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := npy.Read(bytes.NewReader(test.file)); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	m := matrix.Parse("1 2 3\n4 5 6")
+	// This is exactly what numpy.save writes for the same array.
+	expected := npyFile("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }", []float64{1, 2, 3, 4, 5, 6}, binary.LittleEndian)
+	var buffer bytes.Buffer
+	if err := npy.Write(&buffer, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(buffer.Bytes(), expected) {
+		t.Fatalf("expected:\n\n%q\n\ninstead got:\n\n%q", expected, buffer.Bytes())
+	}
+
+	tests := []struct {
+		name    string
+		source  *matrix.Matrix
+		options []npy.Option
+		header  string
+	}{
+		{"float64", m, nil, "'<f8', 'fortran_order': False, 'shape': (2, 3)"},
+		{"float32", m, []npy.Option{npy.WithDType(npy.Float32)}, "'<f4', 'fortran_order': False, 'shape': (2, 3)"},
+		{"uint8", m, []npy.Option{npy.WithDType(npy.Uint8)}, "'|u1', 'fortran_order': False, 'shape': (2, 3)"},
+		{"Fortran order", m, []npy.Option{npy.WithFortranOrder()}, "'<f8', 'fortran_order': True, 'shape': (2, 3)"},
+		{"Transposed", m.Transpose(), nil, "'<f8', 'fortran_order': False, 'shape': (3, 2)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := test.source
+			var buffer bytes.Buffer
+			if err := npy.Write(&buffer, source, test.options...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(buffer.String(), test.header) {
+				t.Fatalf("expected the header to contain %q, instead the file was %q", test.header, buffer.String())
+			}
+			result, err := npy.Read(&buffer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !matrix.Equal(result, source) {
+				t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", source, result)
+			}
+		})
+	}
+}
+
+func TestWriteFloat32(t *testing.T) {
+	m := matrix.ParseDense[float32]("0.1 -2\n1e-30 3")
+	var buffer bytes.Buffer
+	if err := npy.Write(&buffer, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buffer.String(), "'<f4'") {
+		t.Fatalf("expected a float32 matrix to be written as float32, instead the file was %q", buffer.String())
+	}
+	result, err := npy.ReadDense[float32](&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !matrix.Equal(result, m) {
+		t.Fatalf("expected:\n\n%s\n\ninstead got:\n\n%s", m, result)
+	}
+}
+
+func TestWriteUnrepresentable(t *testing.T) {
+	for _, value := range []float64{-1, 256, 0.5, math.NaN()} {
+		m := matrix.NewFromSlice([]float64{0, value}, 1, 2)
+		if err := npy.Write(&bytes.Buffer{}, m, npy.WithDType(npy.Uint8)); !errors.Is(err, npy.ErrUnrepresentable) {
+			t.Fatalf("expected writing %v as a uint8 to return an error wrapping %q, instead got %v", value, npy.ErrUnrepresentable, err)
+		}
+	}
+}
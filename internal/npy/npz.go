@@ -0,0 +1,76 @@
+package npy
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+)
+
+const extension = ".npy"
+
+// ReadArchiveDense reads every array in an .npz archive, as written by
+// numpy.savez or numpy.savez_compressed, into a matrix. The matrices are
+// keyed by the names the arrays were saved with, without the .npy
+// extension. See ReadDense for how arrays become matrices.
+func ReadArchiveDense[T matrix.Float](r io.ReaderAt, size int64) (map[string]*matrix.Dense[T], error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf(unexpectedReadErr, err)
+	}
+	result := make(map[string]*matrix.Dense[T], len(archive.File))
+	for _, file := range archive.File {
+		name := strings.TrimSuffix(file.Name, extension)
+		m, err := readArchiveFile[T](file)
+		if err != nil {
+			return nil, fmt.Errorf("%w (array %q)", err, name)
+		}
+		result[name] = m
+	}
+	return result, nil
+}
+
+func readArchiveFile[T matrix.Float](file *zip.File) (*matrix.Dense[T], error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf(unexpectedReadErr, err)
+	}
+	defer rc.Close()
+	return ReadDense[T](rc)
+}
+
+// ReadArchive reads every array in an .npz archive into a float64 matrix.
+// See ReadArchiveDense.
+func ReadArchive(r io.ReaderAt, size int64) (map[string]*matrix.Matrix, error) {
+	return ReadArchiveDense[float64](r, size)
+}
+
+// WriteArchive writes matrices as an .npz archive that numpy.load reads
+// into a dictionary with the same keys. The arrays are written in order of
+// their names, so the same matrices always produce the same archive.
+func WriteArchive[T matrix.Float](w io.Writer, arrays map[string]*matrix.Dense[T], opts ...Option) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		// numpy.savez stores the arrays without compressing them.
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name + extension, Method: zip.Store})
+		if err != nil {
+			return fmt.Errorf(unexpectedWriteErr, err)
+		}
+		if err := Write(file, arrays[name], opts...); err != nil {
+			return fmt.Errorf("%w (array %q)", err, name)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf(unexpectedWriteErr, err)
+	}
+	return nil
+}
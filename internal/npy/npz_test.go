@@ -0,0 +1,83 @@
+package npy_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/npy"
+)
+
+func TestArchive(t *testing.T) {
+	arrays := map[string]*matrix.Matrix{
+		"images": matrix.Parse("0 255 128\n64 32 16"),
+		"labels": matrix.Parse("3; 7"),
+		"mean":   matrix.Parse("0.5 -1.25"),
+	}
+	var buffer bytes.Buffer
+	if err := npy.WriteArchive(&buffer, arrays); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, name := range []string{"images.npy", "labels.npy", "mean.npy"} {
+		if file := archive.File[i]; file.Name != name || file.Method != zip.Store {
+			t.Fatalf("expected file %d to be %s, stored like numpy.savez does, instead it was %s with method %d", i, name, file.Name, file.Method)
+		}
+	}
+
+	result, err := npy.ReadArchive(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != len(arrays) {
+		t.Fatalf("expected %d arrays, instead got %d", len(arrays), len(result))
+	}
+	for name, expected := range arrays {
+		if m, ok := result[name]; !ok || !matrix.Equal(m, expected) {
+			t.Fatalf("expected %s to be:\n\n%s\n\ninstead got:\n\n%v", name, expected, m)
+		}
+	}
+}
+
+// deflatedArchive returns an .npz archive that deflates each file, as
+// numpy.savez_compressed does.
+func deflatedArchive(t *testing.T, files map[string][]byte) []byte {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for name, contents := range files {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		file.Write(contents)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func TestReadArchiveCompressed(t *testing.T) {
+	x := npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (2,), }", []float32{1.5, 2.5}, binary.LittleEndian)
+	data := deflatedArchive(t, map[string][]byte{"x.npy": x})
+	result, err := npy.ReadArchiveDense[float32](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := matrix.ParseDense[float32]("1.5; 2.5"); !matrix.Equal(result["x"], expected) {
+		t.Fatalf("expected x to be:\n\n%s\n\ninstead got:\n\n%v", expected, result["x"])
+	}
+
+	data = deflatedArchive(t, map[string][]byte{"x.npy": x, "bad.npy": []byte("not an array")})
+	_, err = npy.ReadArchiveDense[float32](bytes.NewReader(data), int64(len(data)))
+	if !errors.Is(err, npy.ErrInvalidMagicNumber) {
+		t.Fatalf("expected an error wrapping %q, instead got %v", npy.ErrInvalidMagicNumber, err)
+	}
+}
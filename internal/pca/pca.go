@@ -0,0 +1,305 @@
+// Package pca reduces the dimensionality of data with principal component
+// analysis, for example to project the 784 pixels of an MNIST image onto a
+// few dozen components for visualization or faster training.
+package pca
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/mnist"
+)
+
+// errorString represents an error in fitting or loading a PCA
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrInvalidComponents specifies that the number of components was less
+	// than 1 or more than the number of features.
+	ErrInvalidComponents errorString = "pca: invalid number of components"
+	// ErrTooFewSamples specifies that there were fewer than the 2 samples
+	// needed to measure variance.
+	ErrTooFewSamples errorString = "pca: there must be at least 2 samples"
+	// ErrImageSize specifies that a set of images don't all have the same
+	// dimensions.
+	ErrImageSize errorString = "pca: the images must all have the same dimensions"
+	// ErrInvalidMagicNumber specifies that the data being loaded did not
+	// have the correct magic number.
+	ErrInvalidMagicNumber errorString = "pca: invalid magic number"
+)
+
+// PCA projects data onto the directions along which a dataset varies the
+// most, its principal components. Data is arranged with one sample per row
+// and one feature per column.
+type PCA struct {
+	// mean is the 1 x features mean of the fitted data, which is subtracted
+	// before projecting.
+	mean *matrix.Matrix
+	// components is components x features, with the principal axes as its
+	// rows in order of decreasing variance.
+	components    *matrix.Matrix
+	variance      []float64
+	totalVariance float64
+}
+
+const (
+	// minOversampling is the fewest extra directions tracked by subspace
+	// iteration, which speed up the convergence of the last components.
+	minOversampling = 10
+	// subspaceIterations is the most iterations subspace iteration makes.
+	subspaceIterations = 1000
+	// residualTolerance is how close to exact the eigenvectors of the
+	// covariance matrix must be, relative to the largest eigenvalue.
+	residualTolerance = 1e-9
+)
+
+// Fit finds the given number of principal components of data, which has a
+// sample in each row. It returns an error wrapping ErrTooFewSamples or
+// ErrInvalidComponents if there is less than 2 samples or components is not
+// between 1 and the number of features.
+//
+// Fit is deterministic: the same data always gives the same components. The
+// sign of each component is chosen so that its largest entry is positive.
+func Fit(data *matrix.Matrix, components int) (*PCA, error) {
+	samples, features := data.Dimensions()
+	if samples < 2 {
+		return nil, fmt.Errorf("%w (%d)", ErrTooFewSamples, samples)
+	}
+	if components < 1 || components > features {
+		return nil, fmt.Errorf("%w: %d components of %d features", ErrInvalidComponents, components, features)
+	}
+
+	mean := matrix.MeanAxis(data, matrix.Rows)
+	centered := matrix.Sub(data, mean)
+	covariance := matrix.Multiply(centered.Transpose(), centered)
+	matrix.ScaleInPlace(covariance, 1/float64(samples-1))
+
+	values, vectors, err := largestEigen(covariance, components)
+	if err != nil {
+		return nil, fmt.Errorf("pca: %w", err)
+	}
+	for c := 0; c < components; c++ {
+		col := vectors.ColView(c)
+		if r, _ := matrix.ArgMax(matrix.Map(col, math.Abs)); col.Get(r, 0) < 0 {
+			matrix.ScaleInPlace(col, -1)
+		}
+	}
+
+	var total float64
+	for i := 0; i < features; i++ {
+		total += covariance.Get(i, i)
+	}
+	return &PCA{
+		mean:          mean,
+		components:    vectors.Transpose().Clone(),
+		variance:      values,
+		totalVariance: total,
+	}, nil
+}
+
+// largestEigen returns the k largest eigenvalues of the symmetric matrix c
+// and their eigenvectors as columns.
+func largestEigen(c *matrix.Matrix, k int) ([]float64, *matrix.Matrix, error) {
+	n, _ := c.Dimensions()
+	// Subspace iteration converges at the rate of the ratio between the
+	// eigenvalue after the subspace and the last one wanted, so tracking
+	// twice as many directions as wanted pays for itself.
+	size := 2 * k
+	if k < minOversampling {
+		size = k + minOversampling
+	}
+	if size >= n/2 {
+		// The full decomposition is cheap enough.
+		eigen, err := matrix.NewEigenSym(c)
+		if err != nil {
+			return nil, nil, err
+		}
+		return eigen.Values()[:k], eigen.Vectors().Slice(0, n, 0, k).Clone(), nil
+	}
+
+	// Subspace iteration: repeatedly multiplying a basis by c and
+	// orthonormalizing it turns it towards the eigenvectors with the largest
+	// eigenvalues. The Rayleigh-Ritz step then finds the best approximations
+	// to them within the basis. A fixed seed keeps Fit deterministic.
+	q := matrix.NewRandomNormalFrom(rand.New(rand.NewSource(0)), n, size)
+	for i := 0; i < subspaceIterations; i++ {
+		qr, err := matrix.NewQR(q)
+		if err != nil {
+			return nil, nil, err
+		}
+		q = qr.Q()
+
+		cq := matrix.Multiply(c, q)
+		h := matrix.Multiply(q.Transpose(), cq)
+		// h is symmetric apart from rounding errors.
+		h = matrix.Scale(matrix.Add(h, h.Transpose()), 0.5)
+		eigen, err := matrix.NewEigenSym(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		values := eigen.Values()[:k]
+		rotation := eigen.Vectors().Slice(0, size, 0, k)
+		vectors := matrix.Multiply(q, rotation)
+
+		// Stop once every eigenvector is accurate: c * v = value * v.
+		scaled := matrix.Hadamard(vectors, matrix.NewFromSlice(values, 1, k))
+		residual := matrix.Sub(matrix.Multiply(cq, rotation), scaled)
+		worst := matrix.Max(matrix.NormAxis(residual, matrix.Rows, matrix.L2))
+		if worst <= residualTolerance*values[0] {
+			return values, vectors, nil
+		}
+		q = cq
+	}
+	return nil, nil, fmt.Errorf("%w: no principal components after %d iterations", matrix.ErrNoConvergence, subspaceIterations)
+}
+
+// Images returns a matrix with a row for each image, holding its pixels
+// scaled to [0, 1] in row-major order. The images must all have the same
+// dimensions, otherwise the error wraps ErrImageSize.
+func Images(images []mnist.Image) (*matrix.Matrix, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf("%w (0)", ErrTooFewSamples)
+	}
+	rows, cols := images[0].Rows, images[0].Cols
+	result := matrix.New(len(images), int(rows*cols))
+	for i, image := range images {
+		if image.Rows != rows || image.Cols != cols || len(image.Pixels) != int(rows*cols) {
+			return nil, fmt.Errorf(
+				"%w: image %d is %dx%d with %d pixels, expected %dx%d",
+				ErrImageSize, i, image.Rows, image.Cols, len(image.Pixels), rows, cols,
+			)
+		}
+		for j, pixel := range image.Pixels {
+			result.Set(i, j, float64(pixel)/255)
+		}
+	}
+	return result, nil
+}
+
+// FitImages fits a PCA to MNIST images, treating each pixel, scaled to
+// [0, 1], as a feature. See Fit and Images.
+func FitImages(images []mnist.Image, components int) (*PCA, error) {
+	data, err := Images(images)
+	if err != nil {
+		return nil, err
+	}
+	return Fit(data, components)
+}
+
+// Components returns a matrix with the principal components as its rows, in
+// order of decreasing variance.
+func (p *PCA) Components() *matrix.Matrix {
+	return p.components.Clone()
+}
+
+// Mean returns the mean of the fitted data as a row vector.
+func (p *PCA) Mean() *matrix.Matrix {
+	return p.mean.Clone()
+}
+
+// ExplainedVariance returns the variance of the fitted data along each
+// principal component.
+func (p *PCA) ExplainedVariance() []float64 {
+	result := make([]float64, len(p.variance))
+	copy(result, p.variance)
+	return result
+}
+
+// ExplainedVarianceRatio returns the fraction of the total variance of the
+// fitted data along each principal component.
+func (p *PCA) ExplainedVarianceRatio() []float64 {
+	result := p.ExplainedVariance()
+	for i := range result {
+		if p.totalVariance == 0 {
+			result[i] = 0
+			continue
+		}
+		result[i] /= p.totalVariance
+	}
+	return result
+}
+
+// Transform projects data, with a sample in each row, onto the principal
+// components, returning a samples x components matrix. It panics with a
+// *matrix.DimensionError if the data doesn't have the number of features
+// that was fitted.
+func (p *PCA) Transform(data *matrix.Matrix) *matrix.Matrix {
+	return matrix.Multiply(matrix.Sub(data, p.mean), p.components.Transpose())
+}
+
+// InverseTransform maps data projected by Transform back to the original
+// features. The result is the closest point to the original data that the
+// components can express.
+func (p *PCA) InverseTransform(reduced *matrix.Matrix) *matrix.Matrix {
+	return matrix.Add(matrix.Multiply(reduced, p.components), p.mean)
+}
+
+const magicNumber int32 = 0x9ca
+
+var (
+	byteOrder = binary.BigEndian
+)
+
+// MarshalBinary encodes the PCA so that it can be saved with a model and
+// loaded again with UnmarshalBinary.
+func (p *PCA) MarshalBinary() ([]byte, error) {
+	components, features := p.components.Dimensions()
+	values := []float64{p.totalVariance}
+	values = append(values, p.variance...)
+	values = append(values, p.mean.Row(0)...)
+	for c := 0; c < components; c++ {
+		values = append(values, p.components.Row(c)...)
+	}
+
+	var buffer bytes.Buffer
+	header := []int32{magicNumber, int32(components), int32(features)}
+	if err := binary.Write(&buffer, byteOrder, header); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buffer, byteOrder, values); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a PCA encoded by MarshalBinary.
+func (p *PCA) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	header := struct {
+		Magic      int32
+		Components int32
+		Features   int32
+	}{}
+	if err := binary.Read(r, byteOrder, &header); err != nil {
+		return fmt.Errorf("pca: unexpected error while reading: %w", err)
+	}
+	if header.Magic != magicNumber {
+		return ErrInvalidMagicNumber
+	}
+	components, features := int(header.Components), int(header.Features)
+	if components < 1 || features < components {
+		return fmt.Errorf("%w: %d components of %d features", ErrInvalidComponents, components, features)
+	}
+	if want := 8 * (1 + components + features + components*features); r.Len() != want {
+		return fmt.Errorf("pca: expected %d bytes of values, instead there are %d", want, r.Len())
+	}
+
+	values := make([]float64, 1+components+features+components*features)
+	if err := binary.Read(r, byteOrder, values); err != nil {
+		return fmt.Errorf("pca: unexpected error while reading: %w", err)
+	}
+	p.totalVariance = values[0]
+	values = values[1:]
+	p.variance, values = values[:components], values[components:]
+	p.mean = matrix.NewFromSlice(values[:features], 1, features)
+	p.components = matrix.NewFromSlice(values[features:], components, features)
+	return nil
+}
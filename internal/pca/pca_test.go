@@ -0,0 +1,207 @@
+package pca_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Anthony-Fiddes/gonne/internal/matrix"
+	"github.com/Anthony-Fiddes/gonne/internal/mnist"
+	"github.com/Anthony-Fiddes/gonne/internal/pca"
+)
+
+// randomData returns samples with features whose standard deviations are
+// 1, 2, ..., features, so that every principal component is distinct.
+func randomData(samples, features int) *matrix.Matrix {
+	data := matrix.NewRandomNormalFrom(rand.New(rand.NewSource(1)), samples, features)
+	for c := 0; c < features; c++ {
+		matrix.ScaleInPlace(data.ColView(c), float64(c+1))
+	}
+	return data
+}
+
+func TestFitLine(t *testing.T) {
+	// Points on the line y = 2x + 1 have all of their variance along it.
+	data := matrix.NewFromSlice([]float64{0, 1, 1, 3, 2, 5, 3, 7}, 4, 2)
+	p, err := pca.Fit(data, 1)
+	if err != nil {
+		t.Fatalf("expected Fit to succeed, instead got %v", err)
+	}
+	direction := matrix.NewFromSlice([]float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)}, 1, 2)
+	if components := p.Components(); !matrix.EqualApprox(components, direction, 1e-12, 0) {
+		t.Fatalf("expected the component:\n%s\ninstead got:\n%s", direction, components)
+	}
+	if ratio := p.ExplainedVarianceRatio(); math.Abs(ratio[0]-1) > 1e-12 {
+		t.Fatalf("expected the component to explain all of the variance, instead it explained %v", ratio)
+	}
+	// The variance of x is 5/3, and the points are sqrt(5) times further
+	// apart along the line.
+	if variance := p.ExplainedVariance(); math.Abs(variance[0]-25.0/3) > 1e-12 {
+		t.Fatalf("expected an explained variance of %f, instead got %v", 25.0/3, variance)
+	}
+	if mean := p.Mean(); mean.String() != "1.5 4" {
+		t.Fatalf("expected a mean of 1.5 4, instead got %s", mean)
+	}
+}
+
+func TestFitMatchesEigen(t *testing.T) {
+	tests := []struct {
+		name                          string
+		samples, features, components int
+	}{
+		{"Full decomposition", 50, 8, 3},
+		{"Subspace iteration", 300, 60, 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := randomData(test.samples, test.features)
+			p, err := pca.Fit(data, test.components)
+			if err != nil {
+				t.Fatalf("expected Fit to succeed, instead got %v", err)
+			}
+
+			centered := matrix.Sub(data, matrix.MeanAxis(data, matrix.Rows))
+			covariance := matrix.Scale(matrix.Multiply(centered.Transpose(), centered), 1/float64(test.samples-1))
+			eigen, err := matrix.NewEigenSym(covariance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values, vectors := eigen.Values(), eigen.Vectors()
+			variance, components := p.ExplainedVariance(), p.Components()
+			for i := 0; i < test.components; i++ {
+				if math.Abs(variance[i]-values[i]) > 1e-9*values[0] {
+					t.Fatalf("expected the explained variances %v, instead got %v", values[:test.components], variance)
+				}
+				// Eigenvectors are only unique up to their sign.
+				alignment := math.Abs(matrix.Dot(components.RowView(i), vectors.ColView(i).Transpose()))
+				if math.Abs(alignment-1) > 1e-8 {
+					t.Fatalf("expected component %d to be an eigenvector, instead its alignment with one was %f", i, alignment)
+				}
+			}
+		})
+	}
+}
+
+func TestTransform(t *testing.T) {
+	data := randomData(40, 6)
+
+	t.Run("All components", func(t *testing.T) {
+		p, err := pca.Fit(data, 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reduced := p.Transform(data)
+		if rows, cols := reduced.Dimensions(); rows != 40 || cols != 6 {
+			t.Fatalf("expected a 40x6 result, instead got %dx%d", rows, cols)
+		}
+		if restored := p.InverseTransform(reduced); !matrix.EqualApprox(restored, data, 1e-10, 1e-10) {
+			t.Fatalf("expected every component to restore the data exactly, instead %v", matrix.Diff(restored, data))
+		}
+	})
+
+	t.Run("Fewer components", func(t *testing.T) {
+		p, err := pca.Fit(data, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reduced := p.Transform(data)
+		// The variance of each projection is the explained variance.
+		variance := matrix.Scale(matrix.VarianceAxis(reduced, matrix.Rows), 40.0/39)
+		expected := matrix.NewFromSlice(p.ExplainedVariance(), 1, 2)
+		if !matrix.EqualApprox(variance, expected, 1e-10, 1e-10) {
+			t.Fatalf("expected the projections to have the variances:\n%s\ninstead got:\n%s", expected, variance)
+		}
+		// Projecting the restored data again changes nothing.
+		if again := p.Transform(p.InverseTransform(reduced)); !matrix.EqualApprox(again, reduced, 1e-10, 1e-10) {
+			t.Fatalf("expected a second projection to match the first, instead %v", matrix.Diff(again, reduced))
+		}
+	})
+}
+
+func TestFitErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       *matrix.Matrix
+		components int
+		want       error
+	}{
+		{"One sample", matrix.New(1, 3), 1, pca.ErrTooFewSamples},
+		{"No components", matrix.New(5, 3), 0, pca.ErrInvalidComponents},
+		{"Too many components", matrix.New(5, 3), 4, pca.ErrInvalidComponents},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := pca.Fit(test.data, test.components); !errors.Is(err, test.want) {
+				t.Fatalf("expected an error wrapping %q, instead got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestFitImages(t *testing.T) {
+	images := []mnist.Image{
+		{Rows: 2, Cols: 2, Pixels: []byte{0, 255, 0, 0}},
+		{Rows: 2, Cols: 2, Pixels: []byte{0, 0, 255, 0}},
+		{Rows: 2, Cols: 2, Pixels: []byte{0, 255, 255, 0}},
+	}
+	data, err := pca.Images(images)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "0 1 0 0\n0 0 1 0\n0 1 1 0"; data.String() != expected {
+		t.Fatalf("expected the pixels:\n%s\ninstead got:\n%s", expected, data)
+	}
+	p, err := pca.FitImages(images, 2)
+	if err != nil {
+		t.Fatalf("expected FitImages to succeed, instead got %v", err)
+	}
+	if restored := p.InverseTransform(p.Transform(data)); !matrix.EqualApprox(restored, data, 1e-12, 1e-12) {
+		t.Fatalf("expected 2 components to describe 3 images in a plane, instead %v", matrix.Diff(restored, data))
+	}
+
+	images = append(images, mnist.Image{Rows: 1, Cols: 4, Pixels: []byte{0, 0, 0, 0}})
+	if _, err := pca.FitImages(images, 2); !errors.Is(err, pca.ErrImageSize) {
+		t.Fatalf("expected an error wrapping %q, instead got %v", pca.ErrImageSize, err)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	data := randomData(30, 5)
+	p, err := pca.Fit(data, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expected MarshalBinary to succeed, instead got %v", err)
+	}
+
+	var loaded pca.PCA
+	if err := loaded.UnmarshalBinary(encoded); err != nil {
+		t.Fatalf("expected UnmarshalBinary to succeed, instead got %v", err)
+	}
+	if want, got := p.Transform(data), loaded.Transform(data); !matrix.Equal(want, got) {
+		t.Fatalf("expected the loaded PCA to transform data identically, instead %v", matrix.Diff(want, got))
+	}
+	ratios, loadedRatios := p.ExplainedVarianceRatio(), loaded.ExplainedVarianceRatio()
+	for i := range ratios {
+		if ratios[i] != loadedRatios[i] {
+			t.Fatalf("expected the explained variance ratios %v, instead got %v", ratios, loadedRatios)
+		}
+	}
+
+	t.Run("Invalid magic number", func(t *testing.T) {
+		corrupt := append([]byte{}, encoded...)
+		corrupt[3]++
+		if err := loaded.UnmarshalBinary(corrupt); !errors.Is(err, pca.ErrInvalidMagicNumber) {
+			t.Fatalf("expected %q, instead got %v", pca.ErrInvalidMagicNumber, err)
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		if err := loaded.UnmarshalBinary(encoded[:len(encoded)-8]); err == nil {
+			t.Fatalf("expected truncated data to be rejected")
+		}
+	})
+}